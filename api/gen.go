@@ -4,6 +4,7 @@
 //go:generate rm -rf ../manifests/crd/bases
 //go:generate go run -tags generate sigs.k8s.io/controller-tools/cmd/controller-gen@v0.16.3 object:headerFile="../hack/copyright.go.txt" paths="./..."
 //go:generate go run -tags generate sigs.k8s.io/controller-tools/cmd/controller-gen@v0.16.3 rbac:roleName=manager-role crd webhook output:crd:artifacts:config=../manifests/crd/bases paths="./..."
+//go:generate go run -tags generate sigs.k8s.io/controller-tools/cmd/controller-gen@v0.16.3 webhook output:webhook:artifacts:config=../manifests/webhook paths="../internal/webhook/..."
 
 package api
 
@@ -0,0 +1,51 @@
+package v1alpha1
+
+// Condition types of the resources.
+const (
+	// ConditionReady indicates the resource is fully reconciled.
+	ConditionReady = "Ready"
+	// ConditionSynced indicates the resource is synchronized with OpenFGA.
+	ConditionSynced = "Synced"
+	// ConditionStoreResolved indicates the referenced store exists and is synchronized.
+	ConditionStoreResolved = "StoreResolved"
+	// ConditionModelValid indicates the model is a valid authorization model.
+	ConditionModelValid = "ModelValid"
+	// ConditionCompatible indicates the model does not orphan tuples in the store.
+	ConditionCompatible = "Compatible"
+	// ConditionTestsPassed indicates the tests of the model passed.
+	ConditionTestsPassed = "TestsPassed"
+	// ConditionConnectionPublished indicates the connection details are published to the config map or secret.
+	ConditionConnectionPublished = "ConnectionPublished"
+)
+
+// Condition reasons of the resources.
+const (
+	ReasonReady                = "Ready"
+	ReasonNotReady             = "NotReady"
+	ReasonSynchronized         = "Synchronized"
+	ReasonResolved             = "Resolved"
+	ReasonValid                = "Valid"
+	ReasonInvalid              = "Invalid"
+	ReasonStoreNotFound        = "StoreNotFound"
+	ReasonStoreNotReady        = "StoreNotReady"
+	ReasonConnectionFailed     = "ConnectionFailed"
+	ReasonAdoptionFailed       = "AdoptionFailed"
+	ReasonCreateFailed         = "CreateFailed"
+	ReasonCompareFailed        = "CompareFailed"
+	ReasonWriteFailed          = "WriteFailed"
+	ReasonPinFailed            = "PinFailed"
+	ReasonCompatible           = "Compatible"
+	ReasonIncompatible         = "Incompatible"
+	ReasonReadFailed           = "ReadFailed"
+	ReasonTestsPassed          = "TestsPassed"
+	ReasonTestsFailed          = "TestsFailed"
+	ReasonTestsError           = "TestsError"
+	ReasonSourceNotFound       = "SourceNotFound"
+	ReasonStoreMissing         = "StoreMissing"
+	ReasonModelMissing         = "ModelMissing"
+	ReasonNotGranted           = "ReferenceNotGranted"
+	ReasonPublished            = "Published"
+	ReasonPublishFailed        = "PublishFailed"
+	ReasonCredentialsNotShared = "CredentialsNotShared"
+	ReasonTargetConflict       = "TargetConflict"
+)
@@ -0,0 +1,47 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:validation:XValidation:rule="has(self.spec.storeRef.kind) && self.spec.storeRef.kind == 'ClusterStore'",message="cluster models can only reference cluster stores"
+//+kubebuilder:validation:XValidation:rule="!has(self.spec.source)",message="cluster models can not load the model from a source"
+//+kubebuilder:validation:XValidation:rule="!has(self.spec.writeConnectionTo)",message="cluster models can not publish connection details"
+
+// ClusterModel is an authorization model of a cluster store, which can be used in all namespaces.
+type ClusterModel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ModelSpec   `json:"spec,omitempty"`
+	Status ModelStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterModelList contains a list of ClusterModels
+type ClusterModelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterModel `json:"items"`
+}
+
+// GetSpec returns the spec of the cluster model.
+func (m *ClusterModel) GetSpec() *ModelSpec {
+	return &m.Spec
+}
+
+// GetStatus returns the status of the cluster model.
+func (m *ClusterModel) GetStatus() *ModelStatus {
+	return &m.Status
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterModel{}, &ClusterModelList{})
+}
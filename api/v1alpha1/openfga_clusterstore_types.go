@@ -0,0 +1,46 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:validation:XValidation:rule="!has(self.spec.serverRef) || has(self.spec.serverRef.__namespace__)",message="the server of a cluster store must have a namespace"
+//+kubebuilder:validation:XValidation:rule="!has(self.spec.writeConnectionTo)",message="cluster stores can not publish connection details"
+
+// ClusterStore is a store shared by all namespaces.
+type ClusterStore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StoreSpec   `json:"spec,omitempty"`
+	Status StoreStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterStoreList contains a list of ClusterStores
+type ClusterStoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterStore `json:"items"`
+}
+
+// GetSpec returns the spec of the cluster store.
+func (s *ClusterStore) GetSpec() *StoreSpec {
+	return &s.Spec
+}
+
+// GetStatus returns the status of the cluster store.
+func (s *ClusterStore) GetStatus() *StoreStatus {
+	return &s.Status
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterStore{}, &ClusterStoreList{})
+}
@@ -1,21 +1,181 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // ModelSpec defines the desired state of Store
+// +kubebuilder:validation:XValidation:rule="(has(self.model) && size(self.model) > 0) != has(self.source)",message="exactly one of model or source must be set"
 type ModelSpec struct {
 	StoreRef StoreRef `json:"storeRef"`
-	Model    string   `json:"model"`
+	// Model is the authorization model in DSL or JSON.
+	// +optional
+	Model string `json:"model,omitempty"`
+	// Format is the format of the model, it is detected from the model if not set.
+	// +optional
+	Format ModelFormat `json:"format,omitempty"`
+	// Source is the source to load the authorization model from instead of the model.
+	// +optional
+	Source *ModelSource `json:"source,omitempty"`
+	// PinnedModelID pins the model to an earlier authorization model ID (e.g. for a rollback).
+	// While pinned, no new authorization models are written to the store.
+	// +optional
+	PinnedModelID string `json:"pinnedModelID,omitempty"`
+	// DeletionPolicy defines what happens to the model when its store is deleted.
+	// Delete removes the model together with the store from the cluster.
+	// Retain and Orphan keep the model in the cluster.
+	// Authorization models are immutable in OpenFGA and are never deleted from the store.
+	// The default of the operator is used if not set.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// DriftPolicy defines what happens when the authorization model was deleted in OpenFGA out-of-band
+	// (e.g. together with its store). Recreate writes the model again, Flag marks the model as failed.
+	// Pinned models are always flagged. The default of the operator is used if not set.
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	// CompatibilityPolicy defines how a model is handled that breaks existing tuples in the store,
+	// e.g. by removing a type or relation or an allowed user type that tuples still use.
+	// Allow writes the model without checking the tuples.
+	// Warn writes the model and reports the orphaned tuples.
+	// Block does not write the model and reports the orphaned tuples.
+	// The model is written without checking the tuples if not set.
+	// +optional
+	CompatibilityPolicy CompatibilityPolicy `json:"compatibilityPolicy,omitempty"`
+	// Tests are run against each new authorization model before it is used.
+	// Each test runs in a temporary store with a copy of the authorization model and the tuples of the test,
+	// so the tuples in the store do not affect the results.
+	// The expectations of the checks are stored as assertions of the authorization model.
+	// +optional
+	Tests []ModelTest `json:"tests,omitempty"`
+	// WriteConnectionTo publishes the API URL, the ID of the store and the ID of the authorization model
+	// to a config map or secret, which is owned by the model and can be consumed by applications (e.g. with envFrom).
+	// +optional
+	WriteConnectionTo *ConnectionTarget `json:"writeConnectionTo,omitempty"`
+	// Paused pauses the control of the model, no authorization models are written while paused.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
 }
 
+// ModelTest defines a test of the model, similar to the tests of `fga model test`.
+type ModelTest struct {
+	// Name is the name of the test.
+	Name string `json:"name"`
+	// Description is the description of the test.
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Tuples are the tuples of the test, they are the only tuples the checks and list objects are run against.
+	// +optional
+	Tuples []TupleKey `json:"tuples,omitempty"`
+	// Check are the expected results of checks.
+	// +optional
+	Check []ModelCheckTest `json:"check,omitempty"`
+	// ListObjects are the expected results of list objects.
+	// +optional
+	ListObjects []ModelListObjectsTest `json:"listObjects,omitempty"`
+}
+
+// ModelCheckTest defines the expected results of checks of a user and an object.
+type ModelCheckTest struct {
+	// User is the user of the checks (e.g. user:anne).
+	User string `json:"user"`
+	// Object is the object of the checks (e.g. document:roadmap).
+	Object string `json:"object"`
+	// Context is the context of the checks.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Context *runtime.RawExtension `json:"context,omitempty"`
+	// Assertions are the expected results of the checks by relation.
+	Assertions map[string]bool `json:"assertions"`
+}
+
+// ModelListObjectsTest defines the expected results of list objects of a user and a type.
+type ModelListObjectsTest struct {
+	// User is the user of the list objects (e.g. user:anne).
+	User string `json:"user"`
+	// Type is the type of the objects (e.g. document).
+	Type string `json:"type"`
+	// Context is the context of the list objects.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Context *runtime.RawExtension `json:"context,omitempty"`
+	// Assertions are the expected objects by relation.
+	Assertions map[string][]string `json:"assertions"`
+}
+
+// ModelSource defines the source of an authorization model.
+// +kubebuilder:validation:XValidation:rule="[has(self.configMapRef), has(self.secretRef), has(self.modules)].filter(x, x).size() == 1",message="exactly one of configMapRef, secretRef or modules must be set"
+type ModelSource struct {
+	// ConfigMapRef is the key of a config map containing the model in DSL.
+	// +optional
+	ConfigMapRef *corev1.ConfigMapKeySelector `json:"configMapRef,omitempty"`
+	// SecretRef is the key of a secret containing the model in DSL.
+	// +optional
+	SecretRef *corev1.SecretKeySelector `json:"secretRef,omitempty"`
+	// Modules is a config map containing a modular model (schema 1.2),
+	// which is combined from the fga.mod file and the module files.
+	// +optional
+	Modules *ModelModules `json:"modules,omitempty"`
+}
+
+// ModelModules defines the config map of a modular model.
+type ModelModules struct {
+	// Name is the name of the config map.
+	Name string `json:"name"`
+	// ModFile is the key of the fga.mod file, it defaults to fga.mod.
+	// +optional
+	ModFile string `json:"modFile,omitempty"`
+	// Items maps the keys of the config map to the paths of the module files in the fga.mod file,
+	// as paths may contain characters that are not allowed in keys.
+	// The keys are used as paths if not set.
+	// +optional
+	Items []corev1.KeyToPath `json:"items,omitempty"`
+}
+
+// ModelFormat defines the format of an authorization model.
+// +kubebuilder:validation:Enum=dsl;json
+type ModelFormat string
+
+const (
+	ModelFormatDSL  ModelFormat = "dsl"
+	ModelFormatJSON ModelFormat = "json"
+)
+
+// CompatibilityPolicy defines how a model is handled that breaks existing tuples.
+// +kubebuilder:validation:Enum=Allow;Warn;Block
+type CompatibilityPolicy string
+
+const (
+	CompatibilityPolicyAllow CompatibilityPolicy = "Allow"
+	CompatibilityPolicyWarn  CompatibilityPolicy = "Warn"
+	CompatibilityPolicyBlock CompatibilityPolicy = "Block"
+)
+
 // StoreRef defines the reference to the store.
+// +kubebuilder:validation:XValidation:rule="!has(self.kind) || self.kind != 'ClusterStore' || !has(self.__namespace__)",message="cluster stores have no namespace"
 type StoreRef struct {
 	// Name is the name of the store.
 	Name string `json:"name"`
+	// Namespace is the namespace of the store, it defaults to the namespace of the referencing resource.
+	// A store in another namespace can only be referenced if a store grant in that namespace permits it.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Kind is the kind of the store, it defaults to Store.
+	// Cluster stores can be referenced from all namespaces.
+	// +optional
+	Kind StoreKind `json:"kind,omitempty"`
 }
 
+// StoreKind defines the kind of a referenced store.
+// +kubebuilder:validation:Enum=Store;ClusterStore
+type StoreKind string
+
+const (
+	StoreKindStore        StoreKind = "Store"
+	StoreKindClusterStore StoreKind = "ClusterStore"
+)
+
 type ModelPhase string
 
 const (
@@ -31,14 +191,100 @@ const (
 type ModelStatus struct {
 	// Phase is the current state of Store.
 	Phase ModelPhase `json:"phase"`
-	// ControlPaused indicates the operator pauses the control of the store.
+	// ControlPaused indicates the operator pauses the control of the model.
 	ControlPaused bool `json:"controlPaused,omitempty"`
 	// InstanceID is the unique identifier of the store.
 	InstanceID string `json:"instanceID"`
+	// StoreID is the unique identifier of the store the authorization model in use is written to.
+	// The model is written again if the store has been recreated with another identifier.
+	StoreID string `json:"storeID,omitempty"`
+	// History are the last authorization models written to the store, newest first.
+	History []ModelRevision `json:"history,omitempty"`
+	// Connection is the config map or secret the connection details were last published to.
+	// It is deleted if the connection details are published elsewhere or no longer published.
+	Connection *PublishedConnection `json:"connection,omitempty"`
+	// Drift is the result of the last comparison of the desired and the written model.
+	Drift *ModelDrift `json:"drift,omitempty"`
+	// Compatibility is the result of the last check of the tuples in the store against the model.
+	Compatibility *ModelCompatibility `json:"compatibility,omitempty"`
+	// Tests is the result of the last run of the tests.
+	Tests *ModelTestResult `json:"tests,omitempty"`
+	// ObservedGeneration is the generation of the model last reconciled by the operator.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastError is the message of the last error, empty if the last reconcile succeeded.
+	LastError string `json:"lastError,omitempty"`
+	// Conditions are the current conditions of the model.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// ModelRevision defines an authorization model written to the store.
+type ModelRevision struct {
+	// ID is the unique identifier of the authorization model.
+	ID string `json:"id"`
+	// SpecHash is the hash of the model spec the authorization model was written from.
+	SpecHash string `json:"specHash"`
+	// Timestamp is the time the authorization model was written.
+	Timestamp metav1.Time `json:"timestamp"`
+	// Generation is the generation of the model the authorization model was written from.
+	Generation int64 `json:"generation"`
+}
+
+// ModelDrift defines the result of the comparison of the desired and the written model.
+type ModelDrift struct {
+	// Detected indicates the desired model differed from the written model.
+	Detected bool `json:"detected"`
+	// LastComparedTime is the time the result of the comparison of the models last changed.
+	LastComparedTime metav1.Time `json:"lastComparedTime,omitempty"`
+	// LastDetectedTime is the time a drift was last detected.
+	LastDetectedTime *metav1.Time `json:"lastDetectedTime,omitempty"`
+}
+
+// ModelCompatibility defines the tuples in the store that are orphaned by the model.
+type ModelCompatibility struct {
+	// Compatible indicates no tuples in the store are orphaned by the model.
+	Compatible bool `json:"compatible"`
+	// LastCheckedTime is the time the tuples were last checked.
+	LastCheckedTime metav1.Time `json:"lastCheckedTime,omitempty"`
+	// OrphanedTupleCount is the number of tuples orphaned by the model.
+	OrphanedTupleCount int `json:"orphanedTupleCount,omitempty"`
+	// OrphanedTuples are the first tuples orphaned by the model.
+	OrphanedTuples []OrphanedTuple `json:"orphanedTuples,omitempty"`
+}
+
+// ModelTestResult defines the result of the tests of an authorization model.
+type ModelTestResult struct {
+	// ModelID is the ID of the authorization model the tests ran against.
+	ModelID string `json:"modelID"`
+	// Generation is the generation of the model the tests ran for.
+	Generation int64 `json:"generation"`
+	// Passed indicates all tests passed.
+	Passed bool `json:"passed"`
+	// LastRunTime is the time the tests last ran.
+	LastRunTime metav1.Time `json:"lastRunTime,omitempty"`
+	// Failures are the first failed expectations.
+	Failures []string `json:"failures,omitempty"`
+}
+
+// OrphanedTuple defines a tuple that is no longer valid with the model.
+type OrphanedTuple struct {
+	// User is the user of the tuple.
+	User string `json:"user"`
+	// Relation is the relation of the tuple.
+	Relation string `json:"relation"`
+	// Object is the object of the tuple.
+	Object string `json:"object"`
+	// Reason is the reason the tuple is no longer valid.
+	Reason string `json:"reason"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 type Model struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -57,6 +303,31 @@ type ModelList struct {
 	Items           []Model `json:"items"`
 }
 
+// GetSpec returns the spec of the model.
+func (m *Model) GetSpec() *ModelSpec {
+	return &m.Spec
+}
+
+// GetStatus returns the status of the model.
+func (m *Model) GetStatus() *ModelStatus {
+	return &m.Status
+}
+
+// GenericModel is a Model or a ClusterModel.
+// +kubebuilder:object:generate=false
+type GenericModel interface {
+	runtime.Object
+	metav1.Object
+
+	GetSpec() *ModelSpec
+	GetStatus() *ModelStatus
+}
+
+var (
+	_ GenericModel = &Model{}
+	_ GenericModel = &ClusterModel{}
+)
+
 func init() {
 	SchemeBuilder.Register(&Model{}, &ModelList{})
 }
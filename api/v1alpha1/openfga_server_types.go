@@ -0,0 +1,94 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServerSpec defines the connection to an OpenFGA server
+type ServerSpec struct {
+	// URL is the URL of the OpenFGA API (e.g. https://openfga.example.com).
+	URL string `json:"url"`
+	// Credentials are the credentials to authenticate with the OpenFGA API.
+	// +optional
+	Credentials *ServerCredentials `json:"credentials,omitempty"`
+	// TLS is the TLS configuration to connect to the OpenFGA API.
+	// +optional
+	TLS *ServerTLS `json:"tls,omitempty"`
+}
+
+// ServerTLS defines the TLS configuration to connect to the OpenFGA API.
+type ServerTLS struct {
+	// CASecretRef is the reference to the secret key containing the PEM encoded CA bundle.
+	// +optional
+	CASecretRef *corev1.SecretKeySelector `json:"caSecretRef,omitempty"`
+	// ClientCertSecretRef is the reference to a kubernetes.io/tls secret containing the client certificate for mutual TLS.
+	// +optional
+	ClientCertSecretRef *corev1.LocalObjectReference `json:"clientCertSecretRef,omitempty"`
+	// ServerName is the name to verify the server certificate against.
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+	// InsecureSkipVerify disables the verification of the server certificate.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// ServerCredentials defines the credentials to authenticate with the OpenFGA API.
+// +kubebuilder:validation:XValidation:rule="!(has(self.apiTokenSecretRef) && has(self.clientCredentials))",message="only one of apiTokenSecretRef and clientCredentials can be set"
+type ServerCredentials struct {
+	// APITokenSecretRef is the reference to the secret key containing the pre-shared key.
+	// +optional
+	APITokenSecretRef *corev1.SecretKeySelector `json:"apiTokenSecretRef,omitempty"`
+	// ClientCredentials are the OAuth2 client credentials.
+	// +optional
+	ClientCredentials *ServerClientCredentials `json:"clientCredentials,omitempty"`
+}
+
+// ServerClientCredentials defines the OAuth2 client credentials.
+type ServerClientCredentials struct {
+	// ClientID is the OAuth2 client ID.
+	ClientID string `json:"clientID"`
+	// ClientSecretRef is the reference to the secret key containing the OAuth2 client secret.
+	ClientSecretRef corev1.SecretKeySelector `json:"clientSecretRef"`
+	// TokenIssuer is the issuer of the OAuth2 tokens (e.g. https://issuer.example.com).
+	TokenIssuer string `json:"tokenIssuer"`
+	// Audience is the audience of the OAuth2 tokens.
+	// +optional
+	Audience string `json:"audience,omitempty"`
+	// Scopes are the scopes of the OAuth2 tokens.
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// ServerRef defines the reference to the server.
+type ServerRef struct {
+	// Name is the name of the server.
+	Name string `json:"name"`
+	// Namespace is the namespace of the server, it is required for cluster stores.
+	// Stores use the server in their own namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`
+
+type Server struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ServerSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ServerList contains a list of Servers
+type ServerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Server `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Server{}, &ServerList{})
+}
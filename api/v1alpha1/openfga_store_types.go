@@ -2,16 +2,107 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 const (
 	AnnotationPrefix = "openfga.zeiss.com/auth."
 	FinalizerName    = "openfga.zeiss.com/finalizer"
+	// PausedAnnotation pauses the control of a resource if set to true.
+	PausedAnnotation = "openfga.zeiss.com/paused"
 )
 
+// DeletionPolicy defines what happens to the OpenFGA resources when a resource is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain;Orphan
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the OpenFGA resources together with the resource.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain keeps the OpenFGA resources when the resource is deleted.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyOrphan keeps the OpenFGA resources and the dependent resources in the cluster.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// DriftPolicy defines how resources are handled that were deleted in OpenFGA out-of-band.
+// +kubebuilder:validation:Enum=Recreate;Flag
+type DriftPolicy string
+
+const (
+	// DriftPolicyRecreate recreates the deleted OpenFGA resources.
+	DriftPolicyRecreate DriftPolicy = "Recreate"
+	// DriftPolicyFlag marks the resource as failed and keeps the OpenFGA resources deleted.
+	DriftPolicyFlag DriftPolicy = "Flag"
+)
+
+// ConnectionKind defines the kind of resource the connection details are published to.
+// +kubebuilder:validation:Enum=ConfigMap;Secret
+type ConnectionKind string
+
+const (
+	ConnectionKindConfigMap ConnectionKind = "ConfigMap"
+	ConnectionKindSecret    ConnectionKind = "Secret"
+)
+
+// ConnectionTarget defines the config map or secret the connection details are published to.
+// +kubebuilder:validation:XValidation:rule="!has(self.includeCredentials) || !self.includeCredentials || self.kind == 'Secret'",message="credentials can only be published to secrets"
+type ConnectionTarget struct {
+	// Name is the name of the config map or secret in the namespace of the resource.
+	Name string `json:"name"`
+	// Kind is the kind of resource the connection details are published to.
+	// +kubebuilder:default=Secret
+	// +optional
+	Kind ConnectionKind `json:"kind,omitempty"`
+	// IncludeCredentials publishes the credentials of the server as well.
+	// Only the credentials of a server in the namespace of the resource are published,
+	// the credentials of the default server of the operator and of the servers of cluster stores are never published.
+	// +optional
+	IncludeCredentials bool `json:"includeCredentials,omitempty"`
+}
+
+// PublishedConnection defines the config map or secret the connection details were published to.
+type PublishedConnection struct {
+	// Name is the name of the config map or secret in the namespace of the resource.
+	Name string `json:"name"`
+	// Kind is the kind of resource the connection details were published to.
+	Kind ConnectionKind `json:"kind"`
+}
+
 // StoreSpec defines the desired state of Store
+// +kubebuilder:validation:XValidation:rule="has(self.serverRef) == has(oldSelf.serverRef) && (!has(self.serverRef) || self.serverRef == oldSelf.serverRef)",message="serverRef is immutable"
 type StoreSpec struct {
+	// StoreRef is the ID of an existing store to adopt instead of creating a new store.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="storeRef is immutable"
+	// +optional
 	StoreRef string `json:"storeRef,omitempty"`
+	// AdoptByName adopts an existing store with the name of the resource.
+	// A new store is created if there is no such store.
+	// +optional
+	AdoptByName bool `json:"adoptByName,omitempty"`
+	// ServerRef is the reference to the server the store is created on.
+	// The default server of the operator is used if not set.
+	// +optional
+	ServerRef *ServerRef `json:"serverRef,omitempty"`
+	// DeletionPolicy defines what happens to the store in OpenFGA when the resource is deleted.
+	// Delete removes the store with all of its models and tuples.
+	// Retain keeps the store, the models and tuples of the store are deleted from the cluster.
+	// Orphan keeps the store and also keeps the models and tuples of the store in the cluster.
+	// The default of the operator is used if not set, adopted stores are retained by default.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// DriftPolicy defines what happens when the store was deleted in OpenFGA out-of-band.
+	// Recreate creates a new store, Flag marks the store as failed.
+	// The default of the operator is used if not set, adopted stores are flagged by default.
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	// WriteConnectionTo publishes the API URL and the ID of the store to a config map or secret,
+	// which is owned by the store and can be consumed by applications (e.g. with envFrom).
+	// +optional
+	WriteConnectionTo *ConnectionTarget `json:"writeConnectionTo,omitempty"`
+	// Paused pauses the control of the store, the store is neither written nor deleted while paused.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
 }
 
 type StorePhase string
@@ -33,10 +124,27 @@ type StoreStatus struct {
 	ControlPaused bool `json:"controlPaused,omitempty"`
 	// StoreID is the unique identifier of the store.
 	StoreID string `json:"storeID"`
+	// Adopted indicates the store existed before and was adopted.
+	Adopted bool `json:"adopted,omitempty"`
+	// Connection is the config map or secret the connection details were last published to.
+	// It is deleted if the connection details are published elsewhere or no longer published.
+	Connection *PublishedConnection `json:"connection,omitempty"`
+	// ObservedGeneration is the generation of the store last reconciled by the operator.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastError is the message of the last error, empty if the last reconcile succeeded.
+	LastError string `json:"lastError,omitempty"`
+	// Conditions are the current conditions of the store.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 type Store struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -55,6 +163,31 @@ type StoreList struct {
 	Items           []Store `json:"items"`
 }
 
+// GetSpec returns the spec of the store.
+func (s *Store) GetSpec() *StoreSpec {
+	return &s.Spec
+}
+
+// GetStatus returns the status of the store.
+func (s *Store) GetStatus() *StoreStatus {
+	return &s.Status
+}
+
+// GenericStore is a Store or a ClusterStore.
+// +kubebuilder:object:generate=false
+type GenericStore interface {
+	runtime.Object
+	metav1.Object
+
+	GetSpec() *StoreSpec
+	GetStatus() *StoreStatus
+}
+
+var (
+	_ GenericStore = &Store{}
+	_ GenericStore = &ClusterStore{}
+)
+
 func init() {
 	SchemeBuilder.Register(&Store{}, &StoreList{})
 }
@@ -0,0 +1,62 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StoreGrantSpec defines the namespaces that may reference stores and models in the namespace of the grant.
+type StoreGrantSpec struct {
+	// From are the namespaces that may reference the stores and models.
+	// +kubebuilder:validation:MinItems=1
+	From []StoreGrantFrom `json:"from"`
+	// To are the stores and models that may be referenced, all stores and models of the namespace if not set.
+	// +optional
+	To []StoreGrantTo `json:"to,omitempty"`
+}
+
+// StoreGrantFrom defines a namespace that may reference stores and models.
+type StoreGrantFrom struct {
+	// Namespace is the name of the namespace.
+	Namespace string `json:"namespace"`
+}
+
+// StoreGrantTo defines the stores or models that may be referenced.
+type StoreGrantTo struct {
+	// Kind is the kind of the referenced resource, a store (e.g. by a model, tuple or tuple set)
+	// or a model (e.g. by a workload).
+	Kind GrantKind `json:"kind"`
+	// Name is the name of the referenced resource, all resources of the kind if not set.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// GrantKind defines the kind of a resource that can be referenced from other namespaces.
+// +kubebuilder:validation:Enum=Store;Model
+type GrantKind string
+
+const (
+	GrantKindStore GrantKind = "Store"
+	GrantKindModel GrantKind = "Model"
+)
+
+//+kubebuilder:object:root=true
+
+type StoreGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec StoreGrantSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// StoreGrantList contains a list of StoreGrants
+type StoreGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StoreGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&StoreGrant{}, &StoreGrantList{})
+}
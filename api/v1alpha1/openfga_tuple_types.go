@@ -0,0 +1,97 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// TupleKey defines a relationship tuple.
+type TupleKey struct {
+	// User is the user of the tuple (e.g. user:anne or team:core#member).
+	User string `json:"user"`
+	// Relation is the relation of the tuple (e.g. viewer).
+	Relation string `json:"relation"`
+	// Object is the object of the tuple (e.g. document:roadmap).
+	Object string `json:"object"`
+	// Condition is the optional condition of the tuple.
+	Condition *TupleCondition `json:"condition,omitempty"`
+}
+
+// String returns the tuple in the object#relation@user notation.
+func (k TupleKey) String() string {
+	return k.Object + "#" + k.Relation + "@" + k.User
+}
+
+// TupleCondition defines the condition of a relationship tuple.
+type TupleCondition struct {
+	// Name is the name of the condition defined in the model.
+	Name string `json:"name"`
+	// Context is the context that is persisted with the condition.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Context *runtime.RawExtension `json:"context,omitempty"`
+}
+
+// TupleSpec defines the desired state of Tuple
+type TupleSpec struct {
+	// StoreRef is the reference to the store the tuple is written to.
+	// Cluster stores are shared by all namespaces and can not be written to by tuples.
+	// +kubebuilder:validation:XValidation:rule="!has(self.kind) || self.kind != 'ClusterStore'",message="tuples can not be written to cluster stores"
+	StoreRef StoreRef `json:"storeRef"`
+
+	TupleKey `json:",inline"`
+
+	// Paused pauses the control of the tuple, the tuple is neither written nor deleted while paused.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+type TuplePhase string
+
+const (
+	TuplePhaseNone         TuplePhase = ""
+	TuplePhasePending      TuplePhase = "Pending"
+	TuplePhaseCreating     TuplePhase = "Creating"
+	TuplePhaseSynchronized TuplePhase = "Synchronized"
+	TuplePhaseFailed       TuplePhase = "Failed"
+)
+
+// TupleStatus defines the observed state of the Tuple
+// +k8s:openapi-gen=true
+type TupleStatus struct {
+	// Phase is the current state of Tuple.
+	Phase TuplePhase `json:"phase"`
+	// ControlPaused indicates the operator pauses the control of the tuple.
+	ControlPaused bool `json:"controlPaused,omitempty"`
+	// StoreID is the unique identifier of the store the tuple is written to.
+	StoreID string `json:"storeID,omitempty"`
+	// StoreRef is the reference to the store the tuple is written to,
+	// which is used to remove it from the store after the reference has changed.
+	StoreRef *StoreRef `json:"storeRef,omitempty"`
+	// Key is the tuple that was last written to the store.
+	Key *TupleKey `json:"key,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+type Tuple struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TupleSpec   `json:"spec,omitempty"`
+	Status TupleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TupleList contains a list of Tuples
+type TupleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Tuple `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Tuple{}, &TupleList{})
+}
@@ -0,0 +1,70 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TupleSetSpec defines the desired state of TupleSet
+type TupleSetSpec struct {
+	// StoreRef is the reference to the store the tuples are written to.
+	// Cluster stores are shared by all namespaces and can not be written to by tuple sets.
+	// +kubebuilder:validation:XValidation:rule="!has(self.kind) || self.kind != 'ClusterStore'",message="tuple sets can not be written to cluster stores"
+	StoreRef StoreRef `json:"storeRef"`
+	// Tuples is the list of tuples that are written to the store.
+	// +optional
+	Tuples []TupleKey `json:"tuples,omitempty"`
+	// Paused pauses the control of the tuple set, no tuples are written or deleted while paused.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+type TupleSetPhase string
+
+const (
+	TupleSetPhaseNone         TupleSetPhase = ""
+	TupleSetPhasePending      TupleSetPhase = "Pending"
+	TupleSetPhaseCreating     TupleSetPhase = "Creating"
+	TupleSetPhaseSynchronized TupleSetPhase = "Synchronized"
+	TupleSetPhaseFailed       TupleSetPhase = "Failed"
+)
+
+// TupleSetStatus defines the observed state of the TupleSet
+// +k8s:openapi-gen=true
+type TupleSetStatus struct {
+	// Phase is the current state of TupleSet.
+	Phase TupleSetPhase `json:"phase"`
+	// ControlPaused indicates the operator pauses the control of the tuple set.
+	ControlPaused bool `json:"controlPaused,omitempty"`
+	// StoreID is the unique identifier of the store the tuples are written to.
+	StoreID string `json:"storeID,omitempty"`
+	// StoreRef is the reference to the store the tuples are written to,
+	// which is used to remove them from the store after the reference has changed.
+	StoreRef *StoreRef `json:"storeRef,omitempty"`
+	// Tuples are the tuples that are owned by the tuple set.
+	// Only these tuples are pruned from the store.
+	Tuples []TupleKey `json:"tuples,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+type TupleSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TupleSetSpec   `json:"spec,omitempty"`
+	Status TupleSetStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TupleSetList contains a list of TupleSets
+type TupleSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TupleSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TupleSet{}, &TupleSetList{})
+}
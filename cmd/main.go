@@ -8,12 +8,14 @@ import (
 	openfgav1alpha1 "github.com/zeiss/openfga-operator/api/v1alpha1"
 	"github.com/zeiss/openfga-operator/controllers"
 	"github.com/zeiss/openfga-operator/internal/config"
-	"github.com/zeiss/openfga-operator/pkg/client"
+	webhookv1 "github.com/zeiss/openfga-operator/internal/webhook/v1"
+	webhookv1alpha1 "github.com/zeiss/openfga-operator/internal/webhook/v1alpha1"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -31,6 +33,8 @@ type flags struct {
 	enableLeaderElection bool
 	metricsAddr          string
 	probeAddr            string
+	enableWebhooks       bool
+	podInjection         bool
 }
 
 var f = &flags{}
@@ -52,6 +56,8 @@ func init() {
 	rootCmd.Flags().BoolVar(&f.enableLeaderElection, "leader-elect", f.enableLeaderElection, "only one controller")
 	rootCmd.Flags().StringVar(&f.metricsAddr, "metrics-bind-address", ":8080", "metrics endpoint")
 	rootCmd.Flags().StringVar(&f.probeAddr, "health-probe-bind-address", ":8081", "health probe")
+	rootCmd.Flags().BoolVar(&f.enableWebhooks, "enable-webhooks", f.enableWebhooks, "admission webhooks")
+	rootCmd.Flags().BoolVar(&f.podInjection, "pod-injection", f.podInjection, "inject models into pods at admission instead of updating workloads in namespaces labeled openfga.zeiss.com/injection=enabled, workloads in other namespaces are still updated (requires --enable-webhooks)")
 
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
@@ -70,8 +76,10 @@ func run(ctx context.Context) error {
 		Scheme:                 scheme,
 		Metrics:                server.Options{BindAddress: f.metricsAddr},
 		HealthProbeBindAddress: f.probeAddr,
-		LeaderElection:         f.enableLeaderElection,
-		LeaderElectionID:       "c7669820.zeiss.com",
+		// workloads are reconciled as unstructured resources, which are read from the cache as well
+		Client:           client.Options{Cache: &client.CacheOptions{Unstructured: true}},
+		LeaderElection:   f.enableLeaderElection,
+		LeaderElectionID: "c7669820.zeiss.com",
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -89,13 +97,20 @@ func run(ctx context.Context) error {
 		return err
 	}
 
+	if f.podInjection && !f.enableWebhooks {
+		return fmt.Errorf("--pod-injection requires --enable-webhooks")
+	}
+
 	cfg := config.New()
 	err = cfg.Marshal()
 	if err != nil {
 		return err
 	}
 
-	fga, err := client.NewClient(cfg.OpenFGAURL)
+	fga := controllers.NewClients(cfg, mgr.GetClient())
+
+	// fail early on an invalid configuration of the default server
+	_, err = fga.Default()
 	if err != nil {
 		return err
 	}
@@ -105,6 +120,13 @@ func run(ctx context.Context) error {
 		return err
 	}
 
+	if f.enableWebhooks {
+		err = setupWebhooks(cfg, mgr)
+		if err != nil {
+			return err
+		}
+	}
+
 	//+kubebuilder:scaffold:builders
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -125,7 +147,7 @@ func run(ctx context.Context) error {
 	return nil
 }
 
-func setupControllers(fga *client.Client, mgr ctrl.Manager) error {
+func setupControllers(fga *controllers.Clients, mgr ctrl.Manager) error {
 	err := controllers.NewStoreReconciler(fga, mgr).SetupWithManager(mgr)
 	if err != nil {
 		return err
@@ -136,11 +158,56 @@ func setupControllers(fga *client.Client, mgr ctrl.Manager) error {
 		return err
 	}
 
-	err = controllers.NewPodReconciler(fga, mgr).SetupWithManager(mgr)
+	err = controllers.NewClusterStoreReconciler(fga, mgr).SetupWithManager(mgr)
+	if err != nil {
+		return err
+	}
+
+	err = controllers.NewClusterModelReconciler(fga, mgr).SetupWithManager(mgr)
+	if err != nil {
+		return err
+	}
+
+	// workloads are not updated in namespaces that are injected at admission
+	pods := controllers.NewPodReconciler(fga, mgr)
+	pods.PodInjection = f.podInjection
+
+	err = pods.SetupWithManager(mgr)
+	if err != nil {
+		return err
+	}
+
+	err = controllers.NewTupleReconciler(fga, mgr).SetupWithManager(mgr)
 	if err != nil {
 		return err
 	}
 
+	err = controllers.NewTupleSetReconciler(fga, mgr).SetupWithManager(mgr)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func setupWebhooks(cfg *config.Config, mgr ctrl.Manager) error {
+	err := webhookv1alpha1.SetupModelWebhookWithManager(mgr)
+	if err != nil {
+		return err
+	}
+
+	err = webhookv1alpha1.SetupClusterModelWebhookWithManager(mgr)
+	if err != nil {
+		return err
+	}
+
+	if f.podInjection {
+		err = webhookv1.SetupPodWebhookWithManager(mgr, cfg.OpenFGAURL)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
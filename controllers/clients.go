@@ -0,0 +1,320 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	openfgav1alpha1 "github.com/zeiss/openfga-operator/api/v1alpha1"
+	"github.com/zeiss/openfga-operator/internal/config"
+	fga "github.com/zeiss/openfga-operator/pkg/client"
+	"github.com/zeiss/pkg/k8s"
+	"github.com/zeiss/pkg/utilx"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const defaultClientKey = "default"
+
+// Clients resolves the OpenFGA client of a store.
+type Clients struct {
+	client.Client
+
+	config *config.Config
+	cache  *fga.Cache
+}
+
+// NewClients ...
+func NewClients(cfg *config.Config, c client.Client) *Clients {
+	return &Clients{
+		Client: c,
+		config: cfg,
+		cache:  fga.NewCache(),
+	}
+}
+
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=servers,verbs=get;list;watch
+
+// Default returns the client of the operator wide server.
+// The client is recreated when the credentials or certificates have been rotated.
+func (c *Clients) Default() (*fga.Client, error) {
+	creds, err := c.config.Credentials()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []fga.Opt{}
+
+	if creds.APIToken != "" {
+		opts = append(opts, fga.WithAPIToken(creds.APIToken))
+	}
+
+	if creds.ClientID != "" {
+		opts = append(opts, fga.WithClientCredentials(fga.ClientCredentials{
+			ClientID:     creds.ClientID,
+			ClientSecret: creds.ClientSecret,
+			TokenIssuer:  creds.APITokenIssuer,
+			Audience:     creds.APIAudience,
+			Scopes:       creds.APIScopes,
+		}))
+	}
+
+	t, err := c.config.TLS()
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Enabled() {
+		opts = append(opts, fga.WithTLS(fga.TLS{
+			CA:                 t.CA,
+			Cert:               t.Cert,
+			Key:                t.Key,
+			ServerName:         t.ServerName,
+			InsecureSkipVerify: t.InsecureSkipVerify,
+		}))
+	}
+
+	b, err := json.Marshal(struct {
+		Credentials config.Credentials
+		TLS         config.TLS
+	}{creds, t})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.cache.Get(defaultClientKey, specHash(c.config.OpenFGAURL+string(b)), func() (*fga.Client, error) {
+		return fga.NewClient(c.config.OpenFGAURL, opts...)
+	})
+}
+
+// ForStore returns the client of the server the store is created on.
+func (c *Clients) ForStore(ctx context.Context, store openfgav1alpha1.GenericStore) (*fga.Client, error) {
+	if store.GetSpec().ServerRef == nil {
+		return c.Default()
+	}
+
+	server := &openfgav1alpha1.Server{}
+	err := c.Get(ctx, serverKey(store), server)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ForServer(ctx, server)
+}
+
+// ForDeletion returns the client of the referenced store to clean up its resources.
+// It returns nil if the store or its server no longer exist, together with everything in them.
+// It also returns nil if the store is being deleted, as the store is deleted or retained as a whole,
+// and if the store is no longer granted to the namespace.
+func (c *Clients) ForDeletion(ctx context.Context, namespace string, ref openfgav1alpha1.StoreRef) (*fga.Client, error) {
+	store, err := FetchStore(ctx, c.Client, namespace, ref)
+	if errors.IsNotFound(err) || IsNotGranted(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !store.GetDeletionTimestamp().IsZero() {
+		return nil, nil
+	}
+
+	fgaClient, err := c.ForStore(ctx, store)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	return fgaClient, err
+}
+
+// DeletionPolicy returns the policy or the default deletion policy of the operator if not set.
+func (c *Clients) DeletionPolicy(policy openfgav1alpha1.DeletionPolicy) openfgav1alpha1.DeletionPolicy {
+	if utilx.NotEmpty(policy) {
+		return policy
+	}
+
+	return openfgav1alpha1.DeletionPolicy(c.config.DefaultDeletionPolicy)
+}
+
+// DriftPolicy returns the policy or the default drift policy of the operator if not set.
+func (c *Clients) DriftPolicy(policy openfgav1alpha1.DriftPolicy) openfgav1alpha1.DriftPolicy {
+	if utilx.NotEmpty(policy) {
+		return policy
+	}
+
+	return openfgav1alpha1.DriftPolicy(c.config.DefaultDriftPolicy)
+}
+
+// DefaultURL returns the URL of the OpenFGA API of the operator wide server.
+func (c *Clients) DefaultURL() string {
+	return c.config.OpenFGAURL
+}
+
+// Resync returns the result to compare a synchronized resource with OpenFGA again after the resync interval.
+func (c *Clients) Resync() reconcile.Result {
+	return reconcile.Result{RequeueAfter: c.config.ResyncInterval}
+}
+
+// ForServer returns the client of the server.
+// Clients are cached and recreated when the server or its secrets change,
+// so that rotated credentials and renewed certificates are picked up.
+func (c *Clients) ForServer(ctx context.Context, server *openfgav1alpha1.Server) (*fga.Client, error) {
+	opts := []fga.Opt{}
+	versions := []string{string(server.UID), fmt.Sprint(server.Generation)}
+
+	if creds := server.Spec.Credentials; creds != nil {
+		if creds.APITokenSecretRef != nil {
+			token, version, err := c.secretValue(ctx, server.Namespace, creds.APITokenSecretRef)
+			if err != nil {
+				return nil, err
+			}
+
+			opts = append(opts, fga.WithAPIToken(token))
+			versions = append(versions, version)
+		}
+
+		if cc := creds.ClientCredentials; cc != nil {
+			secret, version, err := c.secretValue(ctx, server.Namespace, &cc.ClientSecretRef)
+			if err != nil {
+				return nil, err
+			}
+
+			opts = append(opts, fga.WithClientCredentials(fga.ClientCredentials{
+				ClientID:     cc.ClientID,
+				ClientSecret: secret,
+				TokenIssuer:  cc.TokenIssuer,
+				Audience:     cc.Audience,
+				Scopes:       strings.Join(cc.Scopes, " "),
+			}))
+			versions = append(versions, version)
+		}
+	}
+
+	if t := server.Spec.TLS; t != nil {
+		tlsOpts := fga.TLS{
+			ServerName:         t.ServerName,
+			InsecureSkipVerify: t.InsecureSkipVerify,
+		}
+
+		if t.CASecretRef != nil {
+			ca, version, err := c.secretValue(ctx, server.Namespace, t.CASecretRef)
+			if err != nil {
+				return nil, err
+			}
+
+			tlsOpts.CA = []byte(ca)
+			versions = append(versions, version)
+		}
+
+		if t.ClientCertSecretRef != nil {
+			secret := &corev1.Secret{}
+			err := k8s.FetchObject(ctx, c.Client, server.Namespace, t.ClientCertSecretRef.Name, secret)
+			if err != nil {
+				return nil, err
+			}
+
+			tlsOpts.Cert = secret.Data[corev1.TLSCertKey]
+			tlsOpts.Key = secret.Data[corev1.TLSPrivateKeyKey]
+			versions = append(versions, secret.ResourceVersion)
+		}
+
+		opts = append(opts, fga.WithTLS(tlsOpts))
+	}
+
+	key := client.ObjectKeyFromObject(server).String()
+
+	return c.cache.Get(key, strings.Join(versions, "/"), func() (*fga.Client, error) {
+		return fga.NewClient(server.Spec.URL, opts...)
+	})
+}
+
+// Connection returns the connection details of the server the store is created on.
+// The credentials are only included if requested and only of a server in the namespace the details are published to,
+// as anyone who can read them there can use the server. The credentials of the operator and of the servers
+// of cluster stores, which are shared by all namespaces, are never published.
+// It returns ErrCredentialsNotShared if the credentials can not be published to the namespace.
+func (c *Clients) Connection(ctx context.Context, store openfgav1alpha1.GenericStore, namespace string, credentials bool) (map[string]string, error) {
+	if store.GetSpec().ServerRef == nil {
+		if credentials {
+			return nil, fmt.Errorf("%w: the credentials of the operator are never published", ErrCredentialsNotShared)
+		}
+
+		return map[string]string{"OPENFGA_API_URL": c.DefaultURL()}, nil
+	}
+
+	if credentials && utilx.Empty(store.GetNamespace()) {
+		return nil, fmt.Errorf("%w: cluster store %s is shared by all namespaces", ErrCredentialsNotShared, store.GetName())
+	}
+
+	server := &openfgav1alpha1.Server{}
+	err := c.Get(ctx, serverKey(store), server)
+	if err != nil {
+		return nil, err
+	}
+
+	details := map[string]string{"OPENFGA_API_URL": server.Spec.URL}
+	creds := server.Spec.Credentials
+	if !credentials || creds == nil {
+		return details, nil
+	}
+
+	if server.Namespace != namespace {
+		return nil, fmt.Errorf("%w: server %s is not in namespace %s", ErrCredentialsNotShared, client.ObjectKeyFromObject(server), namespace)
+	}
+
+	if creds.APITokenSecretRef != nil {
+		token, _, err := c.secretValue(ctx, server.Namespace, creds.APITokenSecretRef)
+		if err != nil {
+			return nil, err
+		}
+
+		details["OPENFGA_API_TOKEN"] = token
+	}
+
+	if cc := creds.ClientCredentials; cc != nil {
+		secret, _, err := c.secretValue(ctx, server.Namespace, &cc.ClientSecretRef)
+		if err != nil {
+			return nil, err
+		}
+
+		details["OPENFGA_CLIENT_ID"] = cc.ClientID
+		details["OPENFGA_CLIENT_SECRET"] = secret
+		details["OPENFGA_API_TOKEN_ISSUER"] = cc.TokenIssuer
+		details["OPENFGA_API_AUDIENCE"] = cc.Audience
+		details["OPENFGA_API_SCOPES"] = strings.Join(cc.Scopes, " ")
+	}
+
+	return details, nil
+}
+
+// serverKey returns the key of the server of the store.
+// Stores use the server in their own namespace, cluster stores reference the namespace of the server.
+func serverKey(store openfgav1alpha1.GenericStore) client.ObjectKey {
+	ref := store.GetSpec().ServerRef
+	if utilx.Empty(store.GetNamespace()) {
+		return client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}
+	}
+
+	return client.ObjectKey{Namespace: store.GetNamespace(), Name: ref.Name}
+}
+
+// secretValue returns the value of the secret key and the resource version of the secret.
+func (c *Clients) secretValue(ctx context.Context, namespace string, selector *corev1.SecretKeySelector) (string, string, error) {
+	secret := &corev1.Secret{}
+	err := k8s.FetchObject(ctx, c.Client, namespace, selector.Name, secret)
+	if err != nil {
+		return "", "", err
+	}
+
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		return "", "", fmt.Errorf("key %s not found in secret %s", selector.Key, selector.Name)
+	}
+
+	return strings.TrimSpace(string(value)), secret.ResourceVersion, nil
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	openfgav1alpha1 "github.com/zeiss/openfga-operator/api/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// setCondition sets the condition observed at the generation of the resource.
+func setCondition(conditions *[]metav1.Condition, generation int64, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// setReady sets the Ready condition, which is true if all of the conditions are true.
+func setReady(conditions *[]metav1.Condition, generation int64, conditionTypes ...string) {
+	for _, t := range conditionTypes {
+		c := meta.FindStatusCondition(*conditions, t)
+		if c == nil {
+			setCondition(conditions, generation, openfgav1alpha1.ConditionReady, metav1.ConditionFalse, openfgav1alpha1.ReasonNotReady, t+" is not yet reconciled")
+			return
+		}
+
+		if c.Status != metav1.ConditionTrue {
+			setCondition(conditions, generation, openfgav1alpha1.ConditionReady, metav1.ConditionFalse, c.Reason, c.Message)
+			return
+		}
+	}
+
+	setCondition(conditions, generation, openfgav1alpha1.ConditionReady, metav1.ConditionTrue, openfgav1alpha1.ReasonReady, "resource is ready")
+}
+
+// hasReason returns true if the condition is set with the reason.
+func hasReason(conditions []metav1.Condition, conditionType, reason string) bool {
+	c := meta.FindStatusCondition(conditions, conditionType)
+	return c != nil && c.Reason == reason
+}
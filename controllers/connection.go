@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	openfgav1alpha1 "github.com/zeiss/openfga-operator/api/v1alpha1"
+	"github.com/zeiss/pkg/utilx"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	EventReasonConnectionPublished EventReason = "ConnectionPublished"
+	EventReasonConnectionFailed    EventReason = "ConnectionFailed"
+)
+
+// ErrCredentialsNotShared is returned if the credentials of a server can not be published to a namespace.
+var ErrCredentialsNotShared = errors.New("credentials not shared")
+
+// ErrTargetConflict is returned if the config map or secret of a connection target exists and is not controlled by the resource.
+var ErrTargetConflict = errors.New("connection target exists")
+
+// reconcileConnection publishes the connection details to the config map or secret of the target.
+// The config map or secret is owned by the resource and is removed together with it.
+// Existing config maps and secrets that are not controlled by the resource are never taken over,
+// ErrTargetConflict is returned instead.
+func reconcileConnection(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object, target *openfgav1alpha1.ConnectionTarget, details map[string]string) (bool, error) {
+	objectMeta := metav1.ObjectMeta{Name: target.Name, Namespace: owner.GetNamespace()}
+
+	data := map[string]string{}
+	for k, v := range details {
+		if v != "" {
+			data[k] = v
+		}
+	}
+
+	var obj client.Object
+	var mutate func()
+	kind := "secret"
+
+	switch target.Kind {
+	case openfgav1alpha1.ConnectionKindConfigMap:
+		cm := &corev1.ConfigMap{ObjectMeta: objectMeta}
+		obj, mutate, kind = cm, func() { cm.Data = data }, "config map"
+	default:
+		secret := &corev1.Secret{ObjectMeta: objectMeta}
+		obj, mutate = secret, func() {
+			secret.Data = map[string][]byte{}
+			for k, v := range data {
+				secret.Data[k] = []byte(v)
+			}
+		}
+	}
+
+	result, err := controllerutil.CreateOrUpdate(ctx, c, obj, func() error {
+		if utilx.NotEmpty(obj.GetResourceVersion()) && !metav1.IsControlledBy(obj, owner) {
+			return fmt.Errorf("%w: %s %s is not controlled by %s", ErrTargetConflict, kind, target.Name, owner.GetName())
+		}
+
+		mutate()
+		return controllerutil.SetControllerReference(owner, obj, scheme)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return result != controllerutil.OperationResultNone, nil
+}
+
+// publishedConnection returns the config map or secret the connection details are published to for the target.
+func publishedConnection(target *openfgav1alpha1.ConnectionTarget) *openfgav1alpha1.PublishedConnection {
+	if target == nil {
+		return nil
+	}
+
+	kind := target.Kind
+	if utilx.Empty(kind) {
+		kind = openfgav1alpha1.ConnectionKindSecret
+	}
+
+	return &openfgav1alpha1.PublishedConnection{Name: target.Name, Kind: kind}
+}
+
+// removeConnection deletes the config map or secret the connection details were published to before,
+// unless the connection details are still published to it. Config maps and secrets that are not controlled
+// by the resource (anymore) are never deleted.
+func removeConnection(ctx context.Context, c client.Client, owner client.Object, published, target *openfgav1alpha1.PublishedConnection) error {
+	if published == nil || (target != nil && *published == *target) {
+		return nil
+	}
+
+	objectMeta := metav1.ObjectMeta{Name: published.Name, Namespace: owner.GetNamespace()}
+
+	var obj client.Object = &corev1.Secret{ObjectMeta: objectMeta}
+	if published.Kind == openfgav1alpha1.ConnectionKindConfigMap {
+		obj = &corev1.ConfigMap{ObjectMeta: objectMeta}
+	}
+
+	err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if !metav1.IsControlledBy(obj, owner) {
+		return nil
+	}
+
+	return client.IgnoreNotFound(c.Delete(ctx, obj))
+}
+
+// setConnectionPublished records the published connection details in the conditions.
+// It returns true if the conditions changed.
+func setConnectionPublished(conditions *[]metav1.Condition, generation int64, target *openfgav1alpha1.ConnectionTarget) bool {
+	if target == nil {
+		return meta.RemoveStatusCondition(conditions, openfgav1alpha1.ConditionConnectionPublished)
+	}
+
+	c := meta.FindStatusCondition(*conditions, openfgav1alpha1.ConditionConnectionPublished)
+	if c != nil && c.Status == metav1.ConditionTrue && c.ObservedGeneration == generation {
+		return false
+	}
+
+	setCondition(conditions, generation, openfgav1alpha1.ConditionConnectionPublished, metav1.ConditionTrue, openfgav1alpha1.ReasonPublished, "connection details published to "+target.Name)
+
+	return true
+}
+
+// setConnectionFailed records the error of publishing the connection details in the conditions.
+// It returns nil if the error is only resolved by changing the resources, which are reconciled again when changed.
+func setConnectionFailed(conditions *[]metav1.Condition, generation int64, err error) error {
+	reason := openfgav1alpha1.ReasonPublishFailed
+	switch {
+	case errors.Is(err, ErrCredentialsNotShared):
+		reason = openfgav1alpha1.ReasonCredentialsNotShared
+	case errors.Is(err, ErrTargetConflict):
+		reason = openfgav1alpha1.ReasonTargetConflict
+	}
+
+	setCondition(conditions, generation, openfgav1alpha1.ConditionConnectionPublished, metav1.ConditionFalse, reason, err.Error())
+
+	if reason != openfgav1alpha1.ReasonPublishFailed {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	openfgav1alpha1 "github.com/zeiss/openfga-operator/api/v1alpha1"
+	"github.com/zeiss/pkg/utilx"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrNotGranted is returned if a store or model in another namespace is referenced without a store grant.
+var ErrNotGranted = errors.New("reference not granted")
+
+// IsNotGranted returns true if the error is caused by a reference without a store grant.
+func IsNotGranted(err error) bool {
+	return errors.Is(err, ErrNotGranted)
+}
+
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=storegrants,verbs=get;list;watch
+
+// StoreKey returns the key of the store referenced from the namespace, cluster stores have no namespace.
+func StoreKey(namespace string, ref openfgav1alpha1.StoreRef) client.ObjectKey {
+	if ref.Kind == openfgav1alpha1.StoreKindClusterStore {
+		return client.ObjectKey{Name: ref.Name}
+	}
+
+	if utilx.NotEmpty(ref.Namespace) {
+		namespace = ref.Namespace
+	}
+
+	return client.ObjectKey{Namespace: namespace, Name: ref.Name}
+}
+
+// ModelKey returns the key of the model referenced from the namespace by the model.ref annotation,
+// which is either the name of the model or its namespace and name separated by a slash.
+// Cluster models referenced with the model.kind annotation have no namespace.
+func ModelKey(namespace string, annotations map[string]string) client.ObjectKey {
+	ref := annotations[ModelRefAnnotation]
+
+	if annotations[ModelKindAnnotation] == ModelKindClusterModel {
+		return client.ObjectKey{Name: ref}
+	}
+
+	if ns, name, ok := strings.Cut(ref, "/"); ok {
+		return client.ObjectKey{Namespace: ns, Name: name}
+	}
+
+	return client.ObjectKey{Namespace: namespace, Name: ref}
+}
+
+// CheckGrant returns ErrNotGranted if the resource of the kind is in another namespace
+// and no store grant in its namespace permits references from the namespace.
+// Cluster resources can be referenced from all namespaces.
+func CheckGrant(ctx context.Context, c client.Reader, namespace string, kind openfgav1alpha1.GrantKind, key client.ObjectKey) error {
+	if key.Namespace == namespace || utilx.Empty(key.Namespace) {
+		return nil
+	}
+
+	grants := &openfgav1alpha1.StoreGrantList{}
+	err := c.List(ctx, grants, client.InNamespace(key.Namespace))
+	if err != nil {
+		return err
+	}
+
+	for _, grant := range grants.Items {
+		if granted(grant.Spec, namespace, kind, key.Name) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s %s can not be referenced from namespace %s", ErrNotGranted, strings.ToLower(string(kind)), key, namespace)
+}
+
+// FetchStore fetches the store or cluster store referenced from the namespace, if it is granted.
+func FetchStore(ctx context.Context, c client.Reader, namespace string, ref openfgav1alpha1.StoreRef) (openfgav1alpha1.GenericStore, error) {
+	key := StoreKey(namespace, ref)
+
+	err := CheckGrant(ctx, c, namespace, openfgav1alpha1.GrantKindStore, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var store openfgav1alpha1.GenericStore = &openfgav1alpha1.Store{}
+	if ref.Kind == openfgav1alpha1.StoreKindClusterStore {
+		store = &openfgav1alpha1.ClusterStore{}
+	}
+
+	err = c.Get(ctx, key, store)
+	if err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// FetchModel fetches the model or cluster model referenced from the namespace by the annotations, if it is granted.
+func FetchModel(ctx context.Context, c client.Reader, namespace string, annotations map[string]string) (openfgav1alpha1.GenericModel, error) {
+	key := ModelKey(namespace, annotations)
+
+	err := CheckGrant(ctx, c, namespace, openfgav1alpha1.GrantKindModel, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var model openfgav1alpha1.GenericModel = &openfgav1alpha1.Model{}
+	if annotations[ModelKindAnnotation] == ModelKindClusterModel {
+		model = &openfgav1alpha1.ClusterModel{}
+	}
+
+	err = c.Get(ctx, key, model)
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// canOwn returns true if the owner can be set as owner reference of the object,
+// which is not allowed across namespaces. Cluster resources can own objects in all namespaces.
+func canOwn(owner, obj client.Object) bool {
+	return utilx.Empty(owner.GetNamespace()) || owner.GetNamespace() == obj.GetNamespace()
+}
+
+func granted(spec openfgav1alpha1.StoreGrantSpec, namespace string, kind openfgav1alpha1.GrantKind, name string) bool {
+	from := false
+	for _, f := range spec.From {
+		if f.Namespace == namespace {
+			from = true
+			break
+		}
+	}
+
+	if !from {
+		return false
+	}
+
+	// all stores and models of the namespace are granted
+	if len(spec.To) == 0 {
+		return true
+	}
+
+	for _, t := range spec.To {
+		if t.Kind == kind && (utilx.Empty(t.Name) || t.Name == name) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	openfgav1alpha1 "github.com/zeiss/openfga-operator/api/v1alpha1"
+	"github.com/zeiss/pkg/slices"
+	"github.com/zeiss/pkg/utilx"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ModelContainersAnnotation is the comma separated list of containers and init containers the model is injected into.
+	// The model is injected into all containers, but not into init containers, if not set.
+	ModelContainersAnnotation = ModelAnnotationPrefix + "containers"
+	// ModelEnvPrefixAnnotation is the prefix of the injected env vars, it defaults to DefaultEnvPrefix.
+	ModelEnvPrefixAnnotation = ModelAnnotationPrefix + "env-prefix"
+	// ModelCredentialsAnnotation injects references to the credential secrets of the server if set to true.
+	ModelCredentialsAnnotation = ModelAnnotationPrefix + "credentials"
+)
+
+const (
+	// InjectionLabel opts a namespace in to the injection of models into its pods at admission if set to InjectionEnabled.
+	// It requires the pod injection of the operator, the workloads of the namespace are no longer updated.
+	InjectionLabel = "openfga.zeiss.com/injection"
+	// InjectionEnabled is the value of the injection label that enables the injection at admission.
+	InjectionEnabled = "enabled"
+)
+
+// systemNamespaces are never injected at admission, they match the namespace selector of the pod webhook.
+var systemNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+// InjectedAtAdmission returns true if the models are injected into the pods of the namespace at admission.
+func InjectedAtAdmission(ns *corev1.Namespace) bool {
+	return ns.Labels[InjectionLabel] == InjectionEnabled && !slices.In(ns.Name, systemNamespaces...)
+}
+
+// DefaultEnvPrefix is the prefix of the injected env vars.
+const DefaultEnvPrefix = "OPENFGA_"
+
+// ModelEnv returns the env vars of the model, its store and the API URL of its server.
+// The names are prefixed with the env prefix of the annotations. The credentials of the server
+// are referenced from their secrets if requested by the annotations and if the server is in the namespace,
+// but never for cluster stores, which are shared by all namespaces.
+func ModelEnv(ctx context.Context, c client.Reader, defaultURL, namespace string, store openfgav1alpha1.GenericStore, model openfgav1alpha1.GenericModel, annotations map[string]string) ([]corev1.EnvVar, error) {
+	prefix := DefaultEnvPrefix
+	if p, ok := annotations[ModelEnvPrefixAnnotation]; ok {
+		prefix = p
+	}
+
+	env := []corev1.EnvVar{
+		{Name: prefix + "MODEL_INSTANCE_ID", Value: model.GetStatus().InstanceID},
+		{Name: prefix + "MODEL_STORE_ID", Value: store.GetStatus().StoreID},
+	}
+
+	if store.GetSpec().ServerRef == nil {
+		return append(env, corev1.EnvVar{Name: prefix + "API_URL", Value: defaultURL}), nil
+	}
+
+	server := &openfgav1alpha1.Server{}
+	err := c.Get(ctx, serverKey(store), server)
+	if err != nil {
+		return nil, err
+	}
+
+	env = append(env, corev1.EnvVar{Name: prefix + "API_URL", Value: server.Spec.URL})
+
+	// secrets can only be referenced in the namespace of the pod
+	creds := server.Spec.Credentials
+	if annotations[ModelCredentialsAnnotation] != "true" || creds == nil || server.Namespace != namespace || utilx.Empty(store.GetNamespace()) {
+		return env, nil
+	}
+
+	if creds.APITokenSecretRef != nil {
+		env = append(env, corev1.EnvVar{Name: prefix + "API_TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: creds.APITokenSecretRef.DeepCopy()}})
+	}
+
+	if cc := creds.ClientCredentials; cc != nil {
+		env = append(env,
+			corev1.EnvVar{Name: prefix + "CLIENT_ID", Value: cc.ClientID},
+			corev1.EnvVar{Name: prefix + "CLIENT_SECRET", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: cc.ClientSecretRef.DeepCopy()}},
+			corev1.EnvVar{Name: prefix + "API_TOKEN_ISSUER", Value: cc.TokenIssuer},
+		)
+
+		if utilx.NotEmpty(cc.Audience) {
+			env = append(env, corev1.EnvVar{Name: prefix + "API_AUDIENCE", Value: cc.Audience})
+		}
+
+		if len(cc.Scopes) > 0 {
+			env = append(env, corev1.EnvVar{Name: prefix + "API_SCOPES", Value: strings.Join(cc.Scopes, " ")})
+		}
+	}
+
+	return env, nil
+}
+
+// InjectEnv sets the env vars in the containers targeted by the annotations.
+// It returns the names of the containers the env vars are set in.
+func InjectEnv(spec *corev1.PodSpec, env []corev1.EnvVar, annotations map[string]string) []string {
+	targets := []string{}
+	if v, ok := annotations[ModelContainersAnnotation]; ok {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				targets = append(targets, name)
+			}
+		}
+	}
+
+	injected := []string{}
+	inject := func(containers []corev1.Container, all bool) {
+		for i, container := range containers {
+			if !all && !slices.In(container.Name, targets...) {
+				continue
+			}
+
+			containers[i].Env = slices.Unique(func(v corev1.EnvVar) string { return v.Name }, slices.Append(slices.Append([]corev1.EnvVar{}, env...), container.Env...)...)
+			injected = append(injected, container.Name)
+		}
+	}
+
+	inject(spec.InitContainers, false)
+	inject(spec.Containers, len(targets) == 0)
+
+	return injected
+}
+
+// RemoveEnv removes the env vars from the containers and init containers.
+// The env vars are removed from all containers if none are given.
+func RemoveEnv(spec *corev1.PodSpec, names []string, containers []string) {
+	remove := func(list []corev1.Container) {
+		for i, container := range list {
+			if len(containers) > 0 && !slices.In(container.Name, containers...) {
+				continue
+			}
+
+			list[i].Env = slices.Filter(func(v corev1.EnvVar) bool { return !slices.In(v.Name, names...) }, container.Env...)
+		}
+	}
+
+	remove(spec.InitContainers)
+	remove(spec.Containers)
+}
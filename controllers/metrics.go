@@ -0,0 +1,16 @@
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// driftTotal counts the resources that were deleted in OpenFGA out-of-band.
+var driftTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "openfga_operator_drift_total",
+	Help: "Number of resources found deleted in OpenFGA, by kind and drift policy.",
+}, []string{"kind", "policy"})
+
+func init() {
+	metrics.Registry.MustRegister(driftTotal)
+}
@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	openfgav1alpha1 "github.com/zeiss/openfga-operator/api/v1alpha1"
+	fga "github.com/zeiss/openfga-operator/pkg/client"
+	"github.com/zeiss/pkg/cast"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ModelTestFailuresLimit is the number of failed expectations reported in the status of a model.
+const ModelTestFailuresLimit = 20
+
+// ModelTestStorePrefix is the prefix of the names of the temporary stores the tests of a model are run in.
+const ModelTestStorePrefix = "model-tests-"
+
+const (
+	EventReasonModelTestsPassed EventReason = "ModelTestsPassed"
+	EventReasonModelTestsFailed EventReason = "ModelTestsFailed"
+	EventReasonModelPromoted    EventReason = "ModelPromoted"
+)
+
+// reconcileTests runs the tests of the model against the authorization model and stores them as assertions.
+// It returns true if the tests passed and the authorization model can be used.
+// The tests only run again if the authorization model or the model changed.
+func (r *ModelReconciler) reconcileTests(ctx context.Context, fgaClient *fga.Client, store openfgav1alpha1.GenericStore, model openfgav1alpha1.GenericModel, id string) (bool, error) {
+	log := log.FromContext(ctx)
+
+	if len(model.GetSpec().Tests) == 0 {
+		model.GetStatus().Tests = nil
+		meta.RemoveStatusCondition(&model.GetStatus().Conditions, openfgav1alpha1.ConditionTestsPassed)
+
+		return true, nil
+	}
+
+	if t := model.GetStatus().Tests; t != nil && t.ModelID == id && t.Generation == model.GetGeneration() {
+		return t.Passed, nil
+	}
+
+	log.Info("run model tests", "name", model.GetName(), "namespace", model.GetNamespace(), "id", id)
+
+	failures, assertions, err := runModelTests(ctx, fgaClient, store.GetStatus().StoreID, id, model.GetSpec().Tests)
+	if err != nil {
+		return false, err
+	}
+
+	err = fgaClient.WriteAssertions(ctx, store.GetStatus().StoreID, id, assertions)
+	if err != nil {
+		return false, err
+	}
+
+	model.GetStatus().Tests = &openfgav1alpha1.ModelTestResult{
+		ModelID:     id,
+		Generation:  model.GetGeneration(),
+		Passed:      len(failures) == 0,
+		LastRunTime: metav1.Now(),
+		Failures:    failures[:min(len(failures), ModelTestFailuresLimit)],
+	}
+
+	if len(failures) > 0 {
+		msg := fmt.Sprintf("%d expectations failed, e.g. %s", len(failures), failures[0])
+
+		setCondition(&model.GetStatus().Conditions, model.GetGeneration(), openfgav1alpha1.ConditionTestsPassed, metav1.ConditionFalse, openfgav1alpha1.ReasonTestsFailed, msg)
+		r.Recorder.Event(model, corev1.EventTypeWarning, cast.String(EventReasonModelTestsFailed), msg)
+
+		return false, nil
+	}
+
+	setCondition(&model.GetStatus().Conditions, model.GetGeneration(), openfgav1alpha1.ConditionTestsPassed, metav1.ConditionTrue, openfgav1alpha1.ReasonTestsPassed, "all tests passed")
+	r.Recorder.Event(model, corev1.EventTypeNormal, cast.String(EventReasonModelTestsPassed), "all tests passed")
+
+	return true, nil
+}
+
+// runModelTests runs the tests against the authorization model.
+// It returns the failed expectations and the assertions of the checks.
+func runModelTests(ctx context.Context, fgaClient *fga.Client, store, id string, tests []openfgav1alpha1.ModelTest) ([]string, []fga.Assertion, error) {
+	failures := []string{}
+	assertions := []fga.Assertion{}
+
+	for _, test := range tests {
+		f, a, err := runModelTest(ctx, fgaClient, store, id, test)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		failures = append(failures, f...)
+		assertions = append(assertions, a...)
+	}
+
+	return failures, assertions, nil
+}
+
+// runModelTest runs the test in a temporary store with a copy of the authorization model and the tuples of the test,
+// so that the results do not depend on the tuples in the store. The temporary store is deleted afterwards.
+func runModelTest(ctx context.Context, fgaClient *fga.Client, store, id string, test openfgav1alpha1.ModelTest) (failures []string, assertions []fga.Assertion, err error) {
+	tuples, err := toTuples(uniqueTuples(test.Tuples))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tmp, err := fgaClient.CreateStore(ctx, ModelTestStorePrefix+id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		err = errors.Join(err, fgaClient.DeleteStore(ctx, tmp.ID))
+	}()
+
+	m, err := fgaClient.CopyModel(ctx, store, id, tmp.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = fgaClient.WriteTuples(ctx, tmp.ID, tuples)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, check := range test.Check {
+		checkContext, err := toContext(check.Context)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, relation := range slices.Sorted(maps.Keys(check.Assertions)) {
+			expected := check.Assertions[relation]
+			tuple := fga.Tuple{User: check.User, Relation: relation, Object: check.Object}
+
+			allowed, err := fgaClient.Check(ctx, tmp.ID, m.ID, tuple, checkContext, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if allowed != expected {
+				failures = append(failures, fmt.Sprintf("%s: check %s#%s@%s expected %t, got %t", test.Name, check.Object, relation, check.User, expected, allowed))
+			}
+
+			assertions = append(assertions, fga.Assertion{
+				Tuple:            tuple,
+				Expectation:      expected,
+				Context:          checkContext,
+				ContextualTuples: tuples,
+			})
+		}
+	}
+
+	for _, list := range test.ListObjects {
+		listContext, err := toContext(list.Context)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, relation := range slices.Sorted(maps.Keys(list.Assertions)) {
+			expected := slices.Sorted(slices.Values(list.Assertions[relation]))
+
+			objects, err := fgaClient.ListObjects(ctx, tmp.ID, m.ID, list.User, relation, list.Type, listContext, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			slices.Sort(objects)
+			if !slices.Equal(objects, expected) {
+				failures = append(failures, fmt.Sprintf("%s: list objects %s#%s@%s expected %v, got %v", test.Name, list.Type, relation, list.User, expected, objects))
+			}
+		}
+	}
+
+	return failures, assertions, nil
+}
+
+func toContext(raw *runtime.RawExtension) (map[string]interface{}, error) {
+	if raw == nil || len(raw.Raw) == 0 {
+		return nil, nil
+	}
+
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(raw.Raw, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
@@ -2,21 +2,28 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 
 	openfgav1alpha1 "github.com/zeiss/openfga-operator/api/v1alpha1"
 	"github.com/zeiss/pkg/cast"
-	"github.com/zeiss/pkg/k8s"
 	"github.com/zeiss/pkg/k8s/finalizers"
 	"github.com/zeiss/pkg/utilx"
 
 	fga "github.com/zeiss/openfga-operator/pkg/client"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -27,44 +34,86 @@ const (
 	ModelUpdatedAnnotation = ModelAnnotationPrefix + "updated-at"
 )
 
+// ModelHistoryLimit is the number of revisions kept in the status of a model.
+const ModelHistoryLimit = 10
+
+// ModelOrphanedTuplesLimit is the number of orphaned tuples reported in the status of a model.
+const ModelOrphanedTuplesLimit = 50
+
 const (
 	EventReasonModelCreated EventReason = "ModelCreated"
 	EventReasonModelUpdated EventReason = "ModelUpdated"
 	EventReasonModelDeleted EventReason = "ModelDeleted"
 	EventReasonModelFailed  EventReason = "ModelFailed"
+
+	EventReasonModelDriftDetected EventReason = "ModelDriftDetected"
+	EventReasonModelPinned        EventReason = "ModelPinned"
+	EventReasonModelUnpinned      EventReason = "ModelUnpinned"
+	EventReasonModelIncompatible  EventReason = "ModelIncompatible"
+	EventReasonModelMissing       EventReason = "ModelMissing"
 )
 
-// ModelReconciler ...
+// ModelReconciler reconciles models or cluster models.
 type ModelReconciler struct {
 	client.Client
 	Clock
-	FGA      *fga.Client
+	FGA      *Clients
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+
+	// kind is the kind of the reconciled models.
+	kind string
+	// newModel returns an empty model of the kind.
+	newModel func() openfgav1alpha1.GenericModel
+	// newList returns an empty list of models of the kind.
+	newList func() client.ObjectList
 }
 
+// modelStoreIndex indexes the models by the key of the store they reference.
+const modelStoreIndex = ".spec.storeRef"
+
 // NewModelReconciler ...
-func NewModelReconciler(fga *fga.Client, mgr ctrl.Manager) *ModelReconciler {
+func NewModelReconciler(fga *Clients, mgr ctrl.Manager) *ModelReconciler {
+	return &ModelReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor(EventRecorderLabel),
+		FGA:      fga,
+		kind:     "Model",
+		newModel: func() openfgav1alpha1.GenericModel { return &openfgav1alpha1.Model{} },
+		newList:  func() client.ObjectList { return &openfgav1alpha1.ModelList{} },
+	}
+}
+
+// NewClusterModelReconciler ...
+func NewClusterModelReconciler(fga *Clients, mgr ctrl.Manager) *ModelReconciler {
 	return &ModelReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorderFor(EventRecorderLabel),
 		FGA:      fga,
+		kind:     ModelKindClusterModel,
+		newModel: func() openfgav1alpha1.GenericModel { return &openfgav1alpha1.ClusterModel{} },
+		newList:  func() client.ObjectList { return &openfgav1alpha1.ClusterModelList{} },
 	}
 }
 
 //+kubebuilder:rbac:groups=openfga.zeiss.com,resources=models,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=openfga.zeiss.com,resources=models/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=openfga.zeiss.com,resources=models/finalizers,verbs=update
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=clustermodels,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=clustermodels/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=clustermodels/finalizers,verbs=update
 //+kubebuilder:rbac:groups=,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile ...
 func (r *ModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
-	log.Info("reconcile model", "name", req.Name, "namespace", req.Namespace)
+	log.Info("reconcile model", "kind", r.kind, "name", req.Name, "namespace", req.Namespace)
 
-	model := &openfgav1alpha1.Model{}
+	model := r.newModel()
 	if err := r.Get(ctx, req.NamespacedName, model); err != nil {
 		log.Error(err, "model not found", "model", req.NamespacedName)
 		// Request object not found, could have been deleted after reconcile request.
@@ -78,7 +127,17 @@ func (r *ModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return reconcile.Result{}, err
 	}
 
-	if !model.ObjectMeta.DeletionTimestamp.IsZero() {
+	paused, err := reconcilePaused(ctx, r.Client, r.Recorder, model, model.GetSpec().Paused, &model.GetStatus().ControlPaused)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if paused {
+		log.Info("model is paused", "name", model.GetName(), "namespace", model.GetNamespace())
+		return reconcile.Result{}, nil
+	}
+
+	if !model.GetDeletionTimestamp().IsZero() {
 		if finalizers.HasFinalizer(model, openfgav1alpha1.FinalizerName) {
 			err := r.reconcileDelete(ctx, model)
 			if err != nil {
@@ -94,69 +153,367 @@ func (r *ModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{}, nil
+	return r.FGA.Resync(), nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ModelReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), r.newModel(), modelStoreIndex, func(obj client.Object) []string {
+		model := obj.(openfgav1alpha1.GenericModel)
+		return []string{StoreKey(model.GetNamespace(), model.GetSpec().StoreRef).String()}
+	})
+	if err != nil {
+		return err
+	}
+
+	// cluster models have no sources and do not publish connection details
+	if r.kind == ModelKindClusterModel {
+		return ctrl.NewControllerManagedBy(mgr).
+			For(r.newModel(), builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+			Watches(&openfgav1alpha1.Store{}, handler.EnqueueRequestsFromMapFunc(r.findModelsForStore)).
+			Watches(&openfgav1alpha1.ClusterStore{}, handler.EnqueueRequestsFromMapFunc(r.findModelsForStore)).
+			Complete(r)
+	}
+
+	err = indexModelSources(context.Background(), mgr)
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&openfgav1alpha1.Model{}).
-		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{})).
+		For(&openfgav1alpha1.Model{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.findModelsForConfigMap)).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.findModelsForSecret)).
+		Watches(&openfgav1alpha1.StoreGrant{}, handler.EnqueueRequestsFromMapFunc(r.findModelsForGrant)).
+		Watches(&openfgav1alpha1.Store{}, handler.EnqueueRequestsFromMapFunc(r.findModelsForStore)).
+		Watches(&openfgav1alpha1.ClusterStore{}, handler.EnqueueRequestsFromMapFunc(r.findModelsForStore)).
 		Complete(r)
 }
 
-func (r *ModelReconciler) reconcileResources(ctx context.Context, model *openfgav1alpha1.Model) error {
+// findModelsForStore returns the models that reference the store, e.g. to write them once the store is synchronized.
+func (r *ModelReconciler) findModelsForStore(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := r.newList()
+	err := r.List(ctx, list, client.MatchingFields{modelStoreIndex: client.ObjectKeyFromObject(obj).String()})
+	if err != nil {
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	err = meta.EachListItem(list, func(o runtime.Object) error {
+		model, ok := o.(client.Object)
+		if ok {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(model)})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil
+	}
+
+	return requests
+}
+
+// findModelsForGrant returns the models in the namespaces of the grant that reference a store in the namespace of the grant.
+func (r *ModelReconciler) findModelsForGrant(ctx context.Context, obj client.Object) []reconcile.Request {
+	grant, ok := obj.(*openfgav1alpha1.StoreGrant)
+	if !ok {
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, from := range grant.Spec.From {
+		models := &openfgav1alpha1.ModelList{}
+		err := r.List(ctx, models, client.InNamespace(from.Namespace))
+		if err != nil {
+			return nil
+		}
+
+		for _, model := range models.Items {
+			if StoreKey(model.Namespace, model.Spec.StoreRef).Namespace == grant.Namespace {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&model)})
+			}
+		}
+	}
+
+	return requests
+}
+
+func (r *ModelReconciler) reconcileResources(ctx context.Context, model openfgav1alpha1.GenericModel) error {
 	log := log.FromContext(ctx)
 
 	err := r.reconcileStatus(ctx, model)
 	if err != nil {
-		log.Error(err, "failed to reconcile status", "name", model.Name, "namespace", model.Namespace)
+		log.Error(err, "failed to reconcile status", "name", model.GetName(), "namespace", model.GetNamespace())
 		return err
 	}
 
 	err = r.reconcileModel(ctx, model)
 	if err != nil {
-		log.Error(err, "failed to reconcile model", "name", model.Name, "namespace", model.Namespace)
+		log.Error(err, "failed to reconcile model", "name", model.GetName(), "namespace", model.GetNamespace())
+		return err
+	}
+
+	err = r.reconcileConnection(ctx, model)
+	if err != nil {
+		log.Error(err, "failed to publish connection", "name", model.GetName(), "namespace", model.GetNamespace())
 		return err
 	}
 
 	return nil
 }
 
-func (r *ModelReconciler) reconcileModel(ctx context.Context, model *openfgav1alpha1.Model) error {
-	log := log.FromContext(ctx)
+// reconcileConnection publishes the API URL, the ID of the store and the ID of the authorization model in use.
+func (r *ModelReconciler) reconcileConnection(ctx context.Context, model openfgav1alpha1.GenericModel) error {
+	target := model.GetSpec().WriteConnectionTo
+	if utilx.Empty(model.GetStatus().InstanceID) {
+		return nil
+	}
+
+	if target == nil {
+		err := removeConnection(ctx, r.Client, model, model.GetStatus().Connection, nil)
+		if err != nil {
+			return err
+		}
+
+		removed := model.GetStatus().Connection != nil
+		model.GetStatus().Connection = nil
+
+		if setConnectionPublished(&model.GetStatus().Conditions, model.GetGeneration(), nil) || removed {
+			return r.Status().Update(ctx, model)
+		}
+
+		return nil
+	}
+
+	store, err := FetchStore(ctx, r.Client, model.GetNamespace(), model.GetSpec().StoreRef)
+	if err != nil {
+		return err
+	}
+
+	// a store grant permits to use the store, but not to read the credentials of its server
+	if target.IncludeCredentials && store.GetNamespace() != model.GetNamespace() {
+		err = fmt.Errorf("%w: store %s is not in namespace %s", ErrCredentialsNotShared, client.ObjectKeyFromObject(store), model.GetNamespace())
+	}
+
+	changed := false
+	details := map[string]string{}
+	if err == nil {
+		details, err = r.FGA.Connection(ctx, store, model.GetNamespace(), target.IncludeCredentials)
+	}
 
-	log.Info("reconcile model", "name", model.Name, "namespace", model.Namespace)
+	if err == nil {
+		details["OPENFGA_MODEL_STORE_ID"] = store.GetStatus().StoreID
+		details["OPENFGA_MODEL_INSTANCE_ID"] = model.GetStatus().InstanceID
+		changed, err = reconcileConnection(ctx, r.Client, r.Scheme, model, target, details)
+	}
+
+	if err != nil {
+		r.Recorder.Event(model, corev1.EventTypeWarning, cast.String(EventReasonConnectionFailed), "connection details not published: "+err.Error())
+
+		err = setConnectionFailed(&model.GetStatus().Conditions, model.GetGeneration(), err)
+		if err := r.Status().Update(ctx, model); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if changed {
+		r.Recorder.Event(model, corev1.EventTypeNormal, cast.String(EventReasonConnectionPublished), "connection details published to "+target.Name)
+	}
 
-	store := &openfgav1alpha1.Store{}
-	err := k8s.FetchObject(ctx, r.Client, model.Namespace, model.Spec.StoreRef.Name, store)
+	// the connection details were published elsewhere before
+	published := publishedConnection(target)
+	err = removeConnection(ctx, r.Client, model, model.GetStatus().Connection, published)
 	if err != nil {
 		return err
 	}
 
-	log.Info("update model in store", "name", store.Name, "namespace", store.Namespace)
+	moved := !equality.Semantic.DeepEqual(model.GetStatus().Connection, published)
+	model.GetStatus().Connection = published
+
+	if setConnectionPublished(&model.GetStatus().Conditions, model.GetGeneration(), target) || moved {
+		return r.Status().Update(ctx, model)
+	}
+
+	return nil
+}
+
+func (r *ModelReconciler) reconcileModel(ctx context.Context, model openfgav1alpha1.GenericModel) error {
+	log := log.FromContext(ctx)
+
+	log.Info("reconcile model", "name", model.GetName(), "namespace", model.GetNamespace())
+
+	spec, err := r.modelSpec(ctx, model)
+	if err != nil {
+		log.Error(err, "failed to load model source", "name", model.GetName(), "namespace", model.GetNamespace())
+		r.Recorder.Event(model, corev1.EventTypeWarning, cast.String(EventReasonModelFailed), "failed to load model source")
+
+		return r.reconcileFailed(ctx, model, openfgav1alpha1.ConditionModelValid, openfgav1alpha1.ReasonSourceNotFound, err)
+	}
+
+	err = fga.CheckFormat(spec, modelFormat(model))
+	if err == nil {
+		err = fga.ValidateModel(spec)
+	}
+
+	if err != nil {
+		log.Error(err, "invalid model", "name", model.GetName(), "namespace", model.GetNamespace())
+		r.Recorder.Event(model, corev1.EventTypeWarning, cast.String(EventReasonModelFailed), "invalid model")
+
+		// the model is reconciled again when it is changed
+		r.setFailed(model, openfgav1alpha1.ConditionModelValid, openfgav1alpha1.ReasonInvalid, err)
+		return r.Status().Update(ctx, model)
+	}
+
+	setCondition(&model.GetStatus().Conditions, model.GetGeneration(), openfgav1alpha1.ConditionModelValid, metav1.ConditionTrue, openfgav1alpha1.ReasonValid, "model is valid")
+
+	store, err := FetchStore(ctx, r.Client, model.GetNamespace(), model.GetSpec().StoreRef)
+	if IsNotGranted(err) {
+		r.Recorder.Event(model, corev1.EventTypeWarning, cast.String(EventReasonModelFailed), "store "+StoreKey(model.GetNamespace(), model.GetSpec().StoreRef).String()+" is not granted")
+		return r.reconcileFailed(ctx, model, openfgav1alpha1.ConditionStoreResolved, openfgav1alpha1.ReasonNotGranted, err)
+	}
+
+	if err != nil {
+		return r.reconcileFailed(ctx, model, openfgav1alpha1.ConditionStoreResolved, openfgav1alpha1.ReasonStoreNotFound, err)
+	}
+
+	if utilx.Empty(store.GetStatus().StoreID) {
+		return r.reconcileFailed(ctx, model, openfgav1alpha1.ConditionStoreResolved, openfgav1alpha1.ReasonStoreNotReady, fmt.Errorf("store %s is not synchronized", store.GetName()))
+	}
 
-	m, err := r.FGA.UpdateModel(ctx, store.Status.StoreID, model.Spec.Model)
+	fgaClient, err := r.FGA.ForStore(ctx, store)
 	if err != nil {
-		log.Error(err, "failed to update model", "name", model.Name, "namespace", model.Namespace)
+		return r.reconcileFailed(ctx, model, openfgav1alpha1.ConditionStoreResolved, openfgav1alpha1.ReasonConnectionFailed, err)
+	}
+
+	setCondition(&model.GetStatus().Conditions, model.GetGeneration(), openfgav1alpha1.ConditionStoreResolved, metav1.ConditionTrue, openfgav1alpha1.ReasonResolved, "store "+store.GetName()+" is resolved")
+
+	// the deletion policy may have changed since the model was written
+	if utilx.NotEmpty(model.GetStatus().InstanceID) {
+		refs := len(model.GetOwnerReferences())
+
+		err = r.reconcileOwner(store, model)
+		if err != nil {
+			return err
+		}
+
+		if refs != len(model.GetOwnerReferences()) {
+			err = r.update(ctx, model)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if utilx.NotEmpty(model.GetSpec().PinnedModelID) {
+		return r.reconcilePinned(ctx, fgaClient, store, model)
+	}
+
+	flagged, err := r.reconcileDrift(ctx, fgaClient, store, model)
+	if err != nil || flagged {
+		return err
+	}
+
+	latest := latestModelID(model)
+
+	needsUpdate := true
+	if utilx.NotEmpty(latest) {
+		needsUpdate, err = fgaClient.NeedsUpdate(ctx, store.GetStatus().StoreID, latest, spec)
+		if err != nil {
+			log.Error(err, "failed to compare model", "name", model.GetName(), "namespace", model.GetNamespace())
+
+			return r.reconcileFailed(ctx, model, openfgav1alpha1.ConditionSynced, openfgav1alpha1.ReasonCompareFailed, err)
+		}
+	}
+
+	now := metav1.Now()
+	drifted := needsUpdate && utilx.NotEmpty(latest)
+	drift := compareDrift(model.GetStatus().Drift, needsUpdate, drifted, now)
+
+	if drifted {
+		r.Recorder.Event(model, corev1.EventTypeNormal, cast.String(EventReasonModelDriftDetected), "model drift detected")
+	}
+
+	if !needsUpdate {
+		model.GetStatus().Drift = drift
+
+		passed, err := r.reconcileTests(ctx, fgaClient, store, model, latest)
+		if err != nil {
+			return r.reconcileFailed(ctx, model, openfgav1alpha1.ConditionTestsPassed, openfgav1alpha1.ReasonTestsError, err)
+		}
+
+		if !passed {
+			return r.reconcileTestsFailed(ctx, model)
+		}
+
+		// the model was pinned or failed its tests before, return to the latest written model
+		if model.GetStatus().InstanceID != latest {
+			model.GetStatus().InstanceID = latest
+
+			if len(model.GetSpec().Tests) > 0 {
+				r.Recorder.Event(model, corev1.EventTypeNormal, cast.String(EventReasonModelPromoted), "model "+latest+" promoted")
+			} else {
+				r.Recorder.Event(model, corev1.EventTypeNormal, cast.String(EventReasonModelUnpinned), "model unpinned")
+			}
+		}
 
-		model.Status.Phase = openfgav1alpha1.ModelPhaseFailed
+		model.GetStatus().StoreID = store.GetStatus().StoreID
+
+		r.setSynced(model)
 		return r.Status().Update(ctx, model)
 	}
 
-	err = controllerutil.SetOwnerReference(store, model, r.Scheme)
+	err = r.reconcileCompatibility(ctx, fgaClient, store, model, spec)
+	if err != nil {
+		return err
+	}
+
+	log.Info("update model in store", "name", store.GetName(), "namespace", store.GetNamespace())
+
+	m, err := fgaClient.UpdateModel(ctx, store.GetStatus().StoreID, spec)
+	if err != nil {
+		log.Error(err, "failed to update model", "name", model.GetName(), "namespace", model.GetNamespace())
+
+		return r.reconcileFailed(ctx, model, openfgav1alpha1.ConditionSynced, openfgav1alpha1.ReasonWriteFailed, err)
+	}
+
+	err = r.reconcileOwner(store, model)
 	if err != nil {
 		return err
 	}
 
-	model.Finalizers = finalizers.AddFinalizer(model, openfgav1alpha1.FinalizerName)
-	err = r.Update(ctx, model)
+	model.SetFinalizers(finalizers.AddFinalizer(model, openfgav1alpha1.FinalizerName))
+	err = r.update(ctx, model)
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
 
-	model.Status.InstanceID = m.ID
-	model.Status.Phase = openfgav1alpha1.ModelPhaseSynchronized
+	model.GetStatus().History = appendModelRevision(model.GetStatus().History, openfgav1alpha1.ModelRevision{
+		ID:         m.ID,
+		SpecHash:   specHash(spec),
+		Timestamp:  now,
+		Generation: model.GetGeneration(),
+	})
+	model.GetStatus().Drift = drift
+
+	// the new authorization model is only used if its tests pass
+	passed, err := r.reconcileTests(ctx, fgaClient, store, model, m.ID)
+	if err != nil {
+		return r.reconcileFailed(ctx, model, openfgav1alpha1.ConditionTestsPassed, openfgav1alpha1.ReasonTestsError, err)
+	}
+
+	if !passed {
+		return r.reconcileTestsFailed(ctx, model)
+	}
+
+	model.GetStatus().InstanceID = m.ID
+	model.GetStatus().StoreID = store.GetStatus().StoreID
+	model.GetStatus().Phase = openfgav1alpha1.ModelPhaseSynchronized
+	r.setSynced(model)
 	err = r.Status().Update(ctx, model)
 	if err != nil {
 		return err
@@ -167,23 +524,267 @@ func (r *ModelReconciler) reconcileModel(ctx context.Context, model *openfgav1al
 	return nil
 }
 
-func (r *ModelReconciler) reconcileStatus(ctx context.Context, model *openfgav1alpha1.Model) error {
+func (r *ModelReconciler) reconcilePinned(ctx context.Context, fgaClient *fga.Client, store openfgav1alpha1.GenericStore, model openfgav1alpha1.GenericModel) error {
+	log := log.FromContext(ctx)
+
+	if model.GetStatus().InstanceID == model.GetSpec().PinnedModelID && model.GetStatus().StoreID == store.GetStatus().StoreID {
+		return r.reconcileSynced(ctx, model)
+	}
+
+	log.Info("pin model", "name", model.GetName(), "namespace", model.GetNamespace(), "id", model.GetSpec().PinnedModelID)
+
+	_, err := fgaClient.GetAuthorizationModel(ctx, store.GetStatus().StoreID, model.GetSpec().PinnedModelID)
+	if err != nil {
+		log.Error(err, "failed to get pinned model", "name", model.GetName(), "namespace", model.GetNamespace())
+		r.Recorder.Event(model, corev1.EventTypeWarning, cast.String(EventReasonModelFailed), "pinned model not found")
+
+		return r.reconcileFailed(ctx, model, openfgav1alpha1.ConditionSynced, openfgav1alpha1.ReasonPinFailed, err)
+	}
+
+	model.GetStatus().InstanceID = model.GetSpec().PinnedModelID
+	model.GetStatus().StoreID = store.GetStatus().StoreID
+	model.GetStatus().Phase = openfgav1alpha1.ModelPhaseSynchronized
+	r.setSynced(model)
+	err = r.Status().Update(ctx, model)
+	if err != nil {
+		return err
+	}
+
+	r.Recorder.Event(model, corev1.EventTypeNormal, cast.String(EventReasonModelPinned), "model pinned to "+model.GetSpec().PinnedModelID)
+
+	return nil
+}
+
+// reconcileDrift verifies the authorization model in use still exists in OpenFGA.
+// A model that was deleted out-of-band (e.g. together with its store) is written again,
+// unless it is flagged according to the drift policy. It returns true if the model is flagged.
+// A model in use that was written to another store, e.g. before the store was recreated, is always written again.
+func (r *ModelReconciler) reconcileDrift(ctx context.Context, fgaClient *fga.Client, store openfgav1alpha1.GenericStore, model openfgav1alpha1.GenericModel) (bool, error) {
+	log := log.FromContext(ctx)
+
+	if utilx.Empty(model.GetStatus().InstanceID) {
+		return false, nil
+	}
+
+	if utilx.NotEmpty(model.GetStatus().StoreID) && model.GetStatus().StoreID != store.GetStatus().StoreID {
+		log.Info("model written to another store", "name", model.GetName(), "namespace", model.GetNamespace(), "id", model.GetStatus().InstanceID, "store", model.GetStatus().StoreID)
+		return false, nil
+	}
+
+	_, err := fgaClient.GetAuthorizationModel(ctx, store.GetStatus().StoreID, model.GetStatus().InstanceID)
+	if err == nil {
+		return false, nil
+	}
+
+	if !fga.IsModelNotFound(err) {
+		return true, r.reconcileFailed(ctx, model, openfgav1alpha1.ConditionSynced, openfgav1alpha1.ReasonReadFailed, err)
+	}
+
+	policy := r.FGA.DriftPolicy(model.GetSpec().DriftPolicy)
+
+	// flagged models are only reported once
+	if !hasReason(model.GetStatus().Conditions, openfgav1alpha1.ConditionSynced, openfgav1alpha1.ReasonModelMissing) {
+		driftTotal.WithLabelValues(r.kind, string(policy)).Inc()
+
+		log.Info("model deleted in OpenFGA", "name", model.GetName(), "namespace", model.GetNamespace(), "id", model.GetStatus().InstanceID, "policy", policy)
+		r.Recorder.Event(model, corev1.EventTypeWarning, cast.String(EventReasonModelMissing), "model "+model.GetStatus().InstanceID+" deleted in OpenFGA")
+	}
+
+	if policy == openfgav1alpha1.DriftPolicyFlag {
+		// the model is compared again with the next resync
+		r.setFailed(model, openfgav1alpha1.ConditionSynced, openfgav1alpha1.ReasonModelMissing, fmt.Errorf("model %s does not exist in store %s", model.GetStatus().InstanceID, store.GetStatus().StoreID))
+		return true, r.Status().Update(ctx, model)
+	}
+
+	return false, nil
+}
+
+// compareDrift returns the drift status for the result of the comparison of the models.
+// The current status is kept if the result did not change, so that a resync does not update the status of the model.
+func compareDrift(current *openfgav1alpha1.ModelDrift, detected, drifted bool, now metav1.Time) *openfgav1alpha1.ModelDrift {
+	if current != nil && current.Detected == detected && !drifted {
+		return current
+	}
+
+	drift := &openfgav1alpha1.ModelDrift{
+		Detected:         detected,
+		LastComparedTime: now,
+	}
+
+	if current != nil {
+		drift.LastDetectedTime = current.LastDetectedTime
+	}
+
+	if drifted {
+		drift.LastDetectedTime = &now
+	}
+
+	return drift
+}
+
+// reconcileOwner sets the store as the owner of the model, so that the model is deleted together with the store.
+// Models that are retained or orphaned are not owned by the store.
+func (r *ModelReconciler) reconcileOwner(store openfgav1alpha1.GenericStore, model openfgav1alpha1.GenericModel) error {
+	if !canOwn(store, model) {
+		return nil
+	}
+
+	if r.FGA.DeletionPolicy(model.GetSpec().DeletionPolicy) == openfgav1alpha1.DeletionPolicyDelete {
+		return controllerutil.SetOwnerReference(store, model, r.Scheme)
+	}
+
+	owned, err := controllerutil.HasOwnerReference(model.GetOwnerReferences(), store, r.Scheme)
+	if err != nil || !owned {
+		return err
+	}
+
+	return controllerutil.RemoveOwnerReference(store, model, r.Scheme)
+}
+
+// reconcileCompatibility checks the tuples in the store against the model before it is written.
+// It returns an error if the model orphans tuples and the compatibility policy blocks the model.
+func (r *ModelReconciler) reconcileCompatibility(ctx context.Context, fgaClient *fga.Client, store openfgav1alpha1.GenericStore, model openfgav1alpha1.GenericModel, spec string) error {
+	log := log.FromContext(ctx)
+
+	policy := model.GetSpec().CompatibilityPolicy
+	if utilx.Empty(policy) || policy == openfgav1alpha1.CompatibilityPolicyAllow {
+		model.GetStatus().Compatibility = nil
+		meta.RemoveStatusCondition(&model.GetStatus().Conditions, openfgav1alpha1.ConditionCompatible)
+
+		return nil
+	}
+
+	tuples, err := fgaClient.ReadTuples(ctx, store.GetStatus().StoreID)
+	if err != nil {
+		log.Error(err, "failed to read tuples", "name", model.GetName(), "namespace", model.GetNamespace())
+
+		return r.reconcileFailed(ctx, model, openfgav1alpha1.ConditionSynced, openfgav1alpha1.ReasonReadFailed, err)
+	}
+
+	orphaned, err := fga.IncompatibleTuples(spec, tuples)
+	if err != nil {
+		return r.reconcileFailed(ctx, model, openfgav1alpha1.ConditionModelValid, openfgav1alpha1.ReasonInvalid, err)
+	}
+
+	compatibility := &openfgav1alpha1.ModelCompatibility{
+		Compatible:         len(orphaned) == 0,
+		LastCheckedTime:    metav1.Now(),
+		OrphanedTupleCount: len(orphaned),
+	}
+
+	for _, t := range orphaned[:min(len(orphaned), ModelOrphanedTuplesLimit)] {
+		compatibility.OrphanedTuples = append(compatibility.OrphanedTuples, openfgav1alpha1.OrphanedTuple{
+			User:     t.User,
+			Relation: t.Relation,
+			Object:   t.Object,
+			Reason:   t.Reason,
+		})
+	}
+
+	model.GetStatus().Compatibility = compatibility
+
+	if compatibility.Compatible {
+		setCondition(&model.GetStatus().Conditions, model.GetGeneration(), openfgav1alpha1.ConditionCompatible, metav1.ConditionTrue, openfgav1alpha1.ReasonCompatible, "no tuples are orphaned by the model")
+		return nil
+	}
+
+	err = fmt.Errorf("%d tuples are orphaned by the model, e.g. %s#%s@%s: %s", len(orphaned), orphaned[0].Object, orphaned[0].Relation, orphaned[0].User, orphaned[0].Reason)
+
+	log.Info("model orphans tuples", "name", model.GetName(), "namespace", model.GetNamespace(), "count", len(orphaned), "policy", policy)
+	r.Recorder.Event(model, corev1.EventTypeWarning, cast.String(EventReasonModelIncompatible), err.Error())
+
+	setCondition(&model.GetStatus().Conditions, model.GetGeneration(), openfgav1alpha1.ConditionCompatible, metav1.ConditionFalse, openfgav1alpha1.ReasonIncompatible, err.Error())
+
+	if policy == openfgav1alpha1.CompatibilityPolicyBlock {
+		return r.reconcileFailed(ctx, model, openfgav1alpha1.ConditionSynced, openfgav1alpha1.ReasonIncompatible, err)
+	}
+
+	return nil
+}
+
+// update updates the model and keeps the status of the current reconcile,
+// as the update returns the stored status.
+func (r *ModelReconciler) update(ctx context.Context, model openfgav1alpha1.GenericModel) error {
+	status := model.GetStatus().DeepCopy()
+
+	err := r.Update(ctx, model)
+	*model.GetStatus() = cast.Value(status)
+
+	return err
+}
+
+// reconcileSynced updates the conditions of a synchronized model.
+func (r *ModelReconciler) reconcileSynced(ctx context.Context, model openfgav1alpha1.GenericModel) error {
+	current := r.newModel()
+	err := r.Get(ctx, client.ObjectKeyFromObject(model), current)
+	if err != nil {
+		return err
+	}
+
+	r.setSynced(model)
+	if equality.Semantic.DeepEqual(*current.GetStatus(), *model.GetStatus()) {
+		return nil
+	}
+
+	return r.Status().Update(ctx, model)
+}
+
+// reconcileFailed records the error in the status of the model and returns it.
+func (r *ModelReconciler) reconcileFailed(ctx context.Context, model openfgav1alpha1.GenericModel, conditionType, reason string, err error) error {
+	r.setFailed(model, conditionType, reason, err)
+
+	if err := r.Status().Update(ctx, model); err != nil {
+		return err
+	}
+
+	return err
+}
+
+// reconcileTestsFailed records the failed tests in the status of the model.
+// The model is reconciled again when it is changed.
+func (r *ModelReconciler) reconcileTestsFailed(ctx context.Context, model openfgav1alpha1.GenericModel) error {
+	err := fmt.Errorf("tests failed")
+	if c := meta.FindStatusCondition(model.GetStatus().Conditions, openfgav1alpha1.ConditionTestsPassed); c != nil {
+		err = fmt.Errorf("%s", c.Message)
+	}
+
+	r.setFailed(model, openfgav1alpha1.ConditionTestsPassed, openfgav1alpha1.ReasonTestsFailed, err)
+
+	return r.Status().Update(ctx, model)
+}
+
+func (r *ModelReconciler) setFailed(model openfgav1alpha1.GenericModel, conditionType, reason string, err error) {
+	model.GetStatus().Phase = openfgav1alpha1.ModelPhaseFailed
+	model.GetStatus().ObservedGeneration = model.GetGeneration()
+	model.GetStatus().LastError = err.Error()
+	setCondition(&model.GetStatus().Conditions, model.GetGeneration(), conditionType, metav1.ConditionFalse, reason, err.Error())
+	setReady(&model.GetStatus().Conditions, model.GetGeneration(), readyConditions(model)...)
+}
+
+func (r *ModelReconciler) setSynced(model openfgav1alpha1.GenericModel) {
+	model.GetStatus().ObservedGeneration = model.GetGeneration()
+	model.GetStatus().LastError = ""
+	setCondition(&model.GetStatus().Conditions, model.GetGeneration(), openfgav1alpha1.ConditionSynced, metav1.ConditionTrue, openfgav1alpha1.ReasonSynchronized, "model is synchronized")
+	setReady(&model.GetStatus().Conditions, model.GetGeneration(), readyConditions(model)...)
+}
+
+func (r *ModelReconciler) reconcileStatus(ctx context.Context, model openfgav1alpha1.GenericModel) error {
 	log := log.FromContext(ctx)
 
-	log.Info("change status", "name", model.Name, "namespace", model.Namespace)
+	log.Info("change status", "name", model.GetName(), "namespace", model.GetNamespace())
 
 	phase := openfgav1alpha1.ModelPhaseNone
 
-	if utilx.Empty(model.Status.InstanceID) {
+	if utilx.Empty(model.GetStatus().InstanceID) {
 		phase = openfgav1alpha1.ModelPhaseCreating
 	}
 
-	if utilx.NotEmpty(model.Status.InstanceID) {
+	if utilx.NotEmpty(model.GetStatus().InstanceID) {
 		phase = openfgav1alpha1.ModelPhaseSynchronized
 	}
 
-	if model.Status.Phase != phase {
-		model.Status.Phase = phase
+	if model.GetStatus().Phase != phase {
+		model.GetStatus().Phase = phase
 
 		return r.Status().Update(ctx, model)
 	}
@@ -191,10 +792,10 @@ func (r *ModelReconciler) reconcileStatus(ctx context.Context, model *openfgav1a
 	return nil
 }
 
-func (r *ModelReconciler) reconcileDelete(ctx context.Context, model *openfgav1alpha1.Model) error {
+func (r *ModelReconciler) reconcileDelete(ctx context.Context, model openfgav1alpha1.GenericModel) error {
 	log := log.FromContext(ctx)
 
-	log.Info("delete model", "name", model.Name, "namespace", model.Namespace)
+	log.Info("delete model", "name", model.GetName(), "namespace", model.GetNamespace())
 
 	model.SetFinalizers(finalizers.RemoveFinalizer(model, openfgav1alpha1.FinalizerName))
 	err := r.Update(ctx, model)
@@ -204,3 +805,38 @@ func (r *ModelReconciler) reconcileDelete(ctx context.Context, model *openfgav1a
 
 	return nil
 }
+
+// readyConditions returns the conditions the Ready condition of the model depends on.
+func readyConditions(model openfgav1alpha1.GenericModel) []string {
+	conditions := []string{openfgav1alpha1.ConditionModelValid, openfgav1alpha1.ConditionStoreResolved, openfgav1alpha1.ConditionSynced}
+	// pinned models are used without running the tests
+	if len(model.GetSpec().Tests) > 0 && utilx.Empty(model.GetSpec().PinnedModelID) {
+		conditions = append(conditions, openfgav1alpha1.ConditionTestsPassed)
+	}
+
+	return conditions
+}
+
+// latestModelID returns the ID of the latest authorization model written to the store.
+func latestModelID(model openfgav1alpha1.GenericModel) string {
+	if len(model.GetStatus().History) > 0 {
+		return model.GetStatus().History[0].ID
+	}
+
+	return model.GetStatus().InstanceID
+}
+
+// appendModelRevision prepends the revision to the history and keeps at most ModelHistoryLimit revisions.
+func appendModelRevision(history []openfgav1alpha1.ModelRevision, revision openfgav1alpha1.ModelRevision) []openfgav1alpha1.ModelRevision {
+	history = append([]openfgav1alpha1.ModelRevision{revision}, history...)
+	if len(history) > ModelHistoryLimit {
+		history = history[:ModelHistoryLimit]
+	}
+
+	return history
+}
+
+func specHash(spec string) string {
+	sum := sha256.Sum256([]byte(spec))
+	return hex.EncodeToString(sum[:])
+}
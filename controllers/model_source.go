@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	openfgav1alpha1 "github.com/zeiss/openfga-operator/api/v1alpha1"
+	fga "github.com/zeiss/openfga-operator/pkg/client"
+	"github.com/zeiss/pkg/k8s"
+	"github.com/zeiss/pkg/utilx"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	modelConfigMapIndex = ".spec.source.configMap"
+	modelSecretIndex    = ".spec.source.secret"
+)
+
+// modelSpec returns the authorization model of the model, which is loaded from its source if set.
+func (r *ModelReconciler) modelSpec(ctx context.Context, model openfgav1alpha1.GenericModel) (string, error) {
+	source := model.GetSpec().Source
+
+	switch {
+	case source == nil:
+		return model.GetSpec().Model, nil
+	case source.ConfigMapRef != nil:
+		cm := &corev1.ConfigMap{}
+		err := k8s.FetchObject(ctx, r.Client, model.GetNamespace(), source.ConfigMapRef.Name, cm)
+		if err != nil {
+			return "", err
+		}
+
+		value, ok := configMapValue(cm, source.ConfigMapRef.Key)
+		if !ok {
+			return "", fmt.Errorf("key %s not found in config map %s", source.ConfigMapRef.Key, cm.Name)
+		}
+
+		return value, nil
+	case source.SecretRef != nil:
+		value, _, err := r.FGA.secretValue(ctx, model.GetNamespace(), source.SecretRef)
+		if err != nil {
+			return "", err
+		}
+
+		return value, nil
+	case source.Modules != nil:
+		return r.modularModelSpec(ctx, model.GetNamespace(), source.Modules)
+	}
+
+	return "", fmt.Errorf("model %s has no source", model.GetName())
+}
+
+// modelFormat returns the format of the model, modular models are always combined into JSON.
+func modelFormat(model openfgav1alpha1.GenericModel) fga.ModelFormat {
+	if model.GetSpec().Source != nil && model.GetSpec().Source.Modules != nil {
+		return fga.FormatJSON
+	}
+
+	return fga.ModelFormat(model.GetSpec().Format)
+}
+
+// modularModelSpec combines the modules in the config map into a single authorization model.
+func (r *ModelReconciler) modularModelSpec(ctx context.Context, namespace string, modules *openfgav1alpha1.ModelModules) (string, error) {
+	cm := &corev1.ConfigMap{}
+	err := k8s.FetchObject(ctx, r.Client, namespace, modules.Name, cm)
+	if err != nil {
+		return "", err
+	}
+
+	modFile := fga.ModFile
+	if utilx.NotEmpty(modules.ModFile) {
+		modFile = modules.ModFile
+	}
+
+	mod, ok := configMapValue(cm, modFile)
+	if !ok {
+		return "", fmt.Errorf("key %s not found in config map %s", modFile, cm.Name)
+	}
+
+	paths := map[string]string{}
+	for _, item := range modules.Items {
+		paths[item.Key] = item.Path
+	}
+
+	files := map[string]string{}
+	for key := range cm.Data {
+		files[pathOrKey(paths, key)] = cm.Data[key]
+	}
+
+	for key := range cm.BinaryData {
+		files[pathOrKey(paths, key)] = string(cm.BinaryData[key])
+	}
+
+	return fga.TransformModules(mod, files)
+}
+
+// findModelsForConfigMap returns the models that load their authorization model from the config map.
+func (r *ModelReconciler) findModelsForConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.findModels(ctx, obj, modelConfigMapIndex)
+}
+
+// findModelsForSecret returns the models that load their authorization model from the secret.
+func (r *ModelReconciler) findModelsForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.findModels(ctx, obj, modelSecretIndex)
+}
+
+func (r *ModelReconciler) findModels(ctx context.Context, obj client.Object, index string) []reconcile.Request {
+	models := &openfgav1alpha1.ModelList{}
+	err := r.List(ctx, models, client.InNamespace(obj.GetNamespace()), client.MatchingFields{index: obj.GetName()})
+	if err != nil {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(models.Items))
+	for _, model := range models.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&model)})
+	}
+
+	return requests
+}
+
+// indexModelSources indexes the models by the config maps and secrets they load their authorization model from.
+func indexModelSources(ctx context.Context, mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(ctx, &openfgav1alpha1.Model{}, modelConfigMapIndex, func(obj client.Object) []string {
+		source := obj.(*openfgav1alpha1.Model).Spec.Source
+
+		switch {
+		case source == nil:
+			return nil
+		case source.ConfigMapRef != nil:
+			return []string{source.ConfigMapRef.Name}
+		case source.Modules != nil:
+			return []string{source.Modules.Name}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return mgr.GetFieldIndexer().IndexField(ctx, &openfgav1alpha1.Model{}, modelSecretIndex, func(obj client.Object) []string {
+		source := obj.(*openfgav1alpha1.Model).Spec.Source
+		if source == nil || source.SecretRef == nil {
+			return nil
+		}
+
+		return []string{source.SecretRef.Name}
+	})
+}
+
+func configMapValue(cm *corev1.ConfigMap, key string) (string, bool) {
+	if value, ok := cm.Data[key]; ok {
+		return value, true
+	}
+
+	if value, ok := cm.BinaryData[key]; ok {
+		return string(value), true
+	}
+
+	return "", false
+}
+
+func pathOrKey(paths map[string]string, key string) string {
+	if path, ok := paths[key]; ok {
+		return path
+	}
+
+	return key
+}
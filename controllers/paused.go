@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"context"
+	"strconv"
+
+	openfgav1alpha1 "github.com/zeiss/openfga-operator/api/v1alpha1"
+	"github.com/zeiss/pkg/cast"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	EventReasonControlPaused  EventReason = "ControlPaused"
+	EventReasonControlResumed EventReason = "ControlResumed"
+)
+
+// isPaused returns true if the control of the object is paused by its spec or by the paused annotation.
+func isPaused(obj client.Object, paused bool) bool {
+	if paused {
+		return true
+	}
+
+	paused, err := strconv.ParseBool(obj.GetAnnotations()[openfgav1alpha1.PausedAnnotation])
+	if err != nil {
+		return false
+	}
+
+	return paused
+}
+
+// reconcilePaused mirrors the paused state of the object into its status and returns true if it is paused.
+// No OpenFGA resources are written or deleted while the control of an object is paused,
+// objects that are deleted keep their finalizer until they are resumed.
+func reconcilePaused(ctx context.Context, c client.Client, recorder record.EventRecorder, obj client.Object, paused bool, controlPaused *bool) (bool, error) {
+	paused = isPaused(obj, paused)
+	if paused == *controlPaused {
+		return paused, nil
+	}
+
+	*controlPaused = paused
+
+	if paused {
+		recorder.Event(obj, corev1.EventTypeNormal, cast.String(EventReasonControlPaused), "control paused")
+	} else {
+		recorder.Event(obj, corev1.EventTypeNormal, cast.String(EventReasonControlResumed), "control resumed")
+	}
+
+	return paused, c.Status().Update(ctx, obj)
+}
@@ -2,20 +2,23 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
-	fga "github.com/zeiss/openfga-operator/pkg/client"
-
 	openfgav1alpha1 "github.com/zeiss/openfga-operator/api/v1alpha1"
+	fga "github.com/zeiss/openfga-operator/pkg/client"
 	"github.com/zeiss/pkg/cast"
 	"github.com/zeiss/pkg/k8s/finalizers"
 	"github.com/zeiss/pkg/utilx"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -32,24 +35,47 @@ const (
 	EventReasonStoreCreateFailed EventReason = "StoreCreateFailed"
 	EventReasonStoreUpdateFailed EventReason = "StoreUpdateFailed"
 	EventReasonStoreUpdated      EventReason = "StoreUpdated"
+	EventReasonStoreAdopted      EventReason = "StoreAdopted"
+	EventReasonStoreRetained     EventReason = "StoreRetained"
+	EventReasonStoreMissing      EventReason = "StoreMissing"
+	EventReasonStoreRecreated    EventReason = "StoreRecreated"
 )
 
-// StoreReconciler ...
+// StoreReconciler reconciles stores or cluster stores.
 type StoreReconciler struct {
 	client.Client
 	Clock
-	FGA      *fga.Client
+	FGA      *Clients
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+
+	// kind is the kind of the reconciled stores.
+	kind openfgav1alpha1.StoreKind
+	// newStore returns an empty store of the kind.
+	newStore func() openfgav1alpha1.GenericStore
 }
 
 // NewStoreReconciler ...
-func NewStoreReconciler(fga *fga.Client, mgr ctrl.Manager) *StoreReconciler {
+func NewStoreReconciler(fga *Clients, mgr ctrl.Manager) *StoreReconciler {
+	return &StoreReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor(EventRecorderLabel),
+		FGA:      fga,
+		kind:     openfgav1alpha1.StoreKindStore,
+		newStore: func() openfgav1alpha1.GenericStore { return &openfgav1alpha1.Store{} },
+	}
+}
+
+// NewClusterStoreReconciler ...
+func NewClusterStoreReconciler(fga *Clients, mgr ctrl.Manager) *StoreReconciler {
 	return &StoreReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorderFor(EventRecorderLabel),
 		FGA:      fga,
+		kind:     openfgav1alpha1.StoreKindClusterStore,
+		newStore: func() openfgav1alpha1.GenericStore { return &openfgav1alpha1.ClusterStore{} },
 	}
 }
 
@@ -60,22 +86,36 @@ type Clock interface {
 //+kubebuilder:rbac:groups=openfga.zeiss.com,resources=stores,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=openfga.zeiss.com,resources=stores/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=openfga.zeiss.com,resources=stores/finalizers,verbs=update
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=clusterstores,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=clusterstores/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=clusterstores/finalizers,verbs=update
 //+kubebuilder:rbac:groups=,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile ...
 func (r *StoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
-	log.Info("reconcile store", "name", req.Name, "namespace", req.Namespace)
+	log.Info("reconcile store", "kind", r.kind, "name", req.Name, "namespace", req.Namespace)
 
-	store := &openfgav1alpha1.Store{}
+	store := r.newStore()
 	if err := r.Get(ctx, req.NamespacedName, store); err != nil {
 		log.Error(err, "store not found", "store", req.NamespacedName)
 		// Request object not found, could have been deleted after reconcile request.
 		return reconcile.Result{}, client.IgnoreNotFound(err)
 	}
 
-	if !store.ObjectMeta.DeletionTimestamp.IsZero() {
+	paused, err := reconcilePaused(ctx, r.Client, r.Recorder, store, store.GetSpec().Paused, &store.GetStatus().ControlPaused)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if paused {
+		log.Info("store is paused", "name", store.GetName(), "namespace", store.GetNamespace())
+		return reconcile.Result{}, nil
+	}
+
+	if !store.GetDeletionTimestamp().IsZero() {
 		if finalizers.HasFinalizer(store, openfgav1alpha1.FinalizerName) {
 			err := r.reconcileDelete(ctx, store)
 			if err != nil {
@@ -98,84 +138,326 @@ func (r *StoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{}, nil
+	return r.FGA.Resync(), nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// Cluster stores own the cluster models and the models of all namespaces.
 func (r *StoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&openfgav1alpha1.Store{}).
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(r.newStore()).
 		Owns(&openfgav1alpha1.Model{}).
-		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{})).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{})
+
+	if r.kind == openfgav1alpha1.StoreKindClusterStore {
+		b = b.Owns(&openfgav1alpha1.ClusterModel{})
+	}
+
+	return b.WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
 		Complete(r)
 }
 
-func (r *StoreReconciler) reconcileResources(ctx context.Context, s *openfgav1alpha1.Store) error {
+func (r *StoreReconciler) reconcileResources(ctx context.Context, store openfgav1alpha1.GenericStore) error {
 	log := log.FromContext(ctx)
 
-	err := r.reconcileStatus(ctx, s)
+	err := r.reconcileStatus(ctx, store)
 	if err != nil {
-		log.Error(err, "failed to reconcile status", "name", s.Name, "namespace", s.Namespace)
+		log.Error(err, "failed to reconcile status", "name", store.GetName(), "namespace", store.GetNamespace())
 		return err
 	}
 
-	err = r.reconcileStore(ctx, s)
+	err = r.reconcileStore(ctx, store)
 	if err != nil {
-		log.Error(err, "failed to reconcile store", "name", s.Name, "namespace", s.Namespace)
+		log.Error(err, "failed to reconcile store", "name", store.GetName(), "namespace", store.GetNamespace())
+		return err
+	}
+
+	err = r.reconcileConnection(ctx, store)
+	if err != nil {
+		log.Error(err, "failed to publish connection", "name", store.GetName(), "namespace", store.GetNamespace())
 		return err
 	}
 
 	return nil
 }
 
-func (r *StoreReconciler) reconcileStore(ctx context.Context, store *openfgav1alpha1.Store) error {
-	log := log.FromContext(ctx)
+// reconcileConnection publishes the API URL and the ID of a synchronized store.
+func (r *StoreReconciler) reconcileConnection(ctx context.Context, store openfgav1alpha1.GenericStore) error {
+	target := store.GetSpec().WriteConnectionTo
+	if utilx.Empty(store.GetStatus().StoreID) {
+		return nil
+	}
+
+	if target == nil {
+		err := removeConnection(ctx, r.Client, store, store.GetStatus().Connection, nil)
+		if err != nil {
+			return err
+		}
+
+		removed := store.GetStatus().Connection != nil
+		store.GetStatus().Connection = nil
 
-	log.Info("reconcile resource", "name", store.Name, "namespace", store.Namespace)
+		if setConnectionPublished(&store.GetStatus().Conditions, store.GetGeneration(), nil) || removed {
+			return r.Status().Update(ctx, store)
+		}
 
-	if utilx.NotEmpty(store.Status.StoreID) {
 		return nil
 	}
 
-	s, err := r.FGA.CreateStore(ctx, store.Name)
+	changed := false
+	details, err := r.FGA.Connection(ctx, store, store.GetNamespace(), target.IncludeCredentials)
+	if err == nil {
+		details["OPENFGA_STORE_ID"] = store.GetStatus().StoreID
+		changed, err = reconcileConnection(ctx, r.Client, r.Scheme, store, target, details)
+	}
+
 	if err != nil {
+		r.Recorder.Event(store, corev1.EventTypeWarning, cast.String(EventReasonConnectionFailed), "connection details not published: "+err.Error())
+
+		err = setConnectionFailed(&store.GetStatus().Conditions, store.GetGeneration(), err)
+		if err := r.Status().Update(ctx, store); err != nil {
+			return err
+		}
+
 		return err
 	}
 
-	store.Finalizers = finalizers.AddFinalizer(store, openfgav1alpha1.FinalizerName)
+	if changed {
+		r.Recorder.Event(store, corev1.EventTypeNormal, cast.String(EventReasonConnectionPublished), "connection details published to "+target.Name)
+	}
+
+	// the connection details were published elsewhere before
+	published := publishedConnection(target)
+	err = removeConnection(ctx, r.Client, store, store.GetStatus().Connection, published)
+	if err != nil {
+		return err
+	}
+
+	moved := !equality.Semantic.DeepEqual(store.GetStatus().Connection, published)
+	store.GetStatus().Connection = published
+
+	if setConnectionPublished(&store.GetStatus().Conditions, store.GetGeneration(), target) || moved {
+		return r.Status().Update(ctx, store)
+	}
+
+	return nil
+}
+
+func (r *StoreReconciler) reconcileStore(ctx context.Context, store openfgav1alpha1.GenericStore) error {
+	log := log.FromContext(ctx)
+
+	log.Info("reconcile resource", "name", store.GetName(), "namespace", store.GetNamespace())
+
+	if utilx.NotEmpty(store.GetStatus().StoreID) {
+		return r.reconcileDrift(ctx, store)
+	}
+
+	fgaClient, err := r.FGA.ForStore(ctx, store)
+	if err != nil {
+		return r.reconcileFailed(ctx, store, openfgav1alpha1.ReasonConnectionFailed, err)
+	}
+
+	s, adopted, err := r.adoptStore(ctx, fgaClient, store)
+	if err != nil {
+		log.Error(err, "failed to adopt store", "name", store.GetName(), "namespace", store.GetNamespace())
+		r.Recorder.Event(store, corev1.EventTypeWarning, cast.String(EventReasonStoreFetchFailed), "store adoption failed")
+
+		return r.reconcileFailed(ctx, store, openfgav1alpha1.ReasonAdoptionFailed, err)
+	}
+
+	if s == nil {
+		s, err = fgaClient.CreateStore(ctx, store.GetName())
+		if err != nil {
+			r.Recorder.Event(store, corev1.EventTypeWarning, cast.String(EventReasonStoreCreateFailed), "store create failed")
+
+			return r.reconcileFailed(ctx, store, openfgav1alpha1.ReasonCreateFailed, err)
+		}
+	}
+
+	store.SetFinalizers(finalizers.AddFinalizer(store, openfgav1alpha1.FinalizerName))
 	err = r.Update(ctx, store)
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
 
-	store.Status.StoreID = s.ID
+	store.GetStatus().StoreID = s.ID
+	store.GetStatus().Adopted = adopted
+	store.GetStatus().Phase = openfgav1alpha1.StorePhaseSynchronized
+	r.setSynced(store)
 	err = r.Status().Update(ctx, store)
 	if err != nil {
 		r.Recorder.Event(store, corev1.EventTypeWarning, cast.String(EventReasonStoreUpdateFailed), "store update failed")
 		return err
 	}
 
+	if adopted {
+		r.Recorder.Event(store, corev1.EventTypeNormal, cast.String(EventReasonStoreAdopted), "store adopted")
+		return nil
+	}
+
 	r.Recorder.Event(store, corev1.EventTypeNormal, cast.String(EventReasonStoreUpdated), "store updated")
 
 	return nil
 }
 
-func (r *StoreReconciler) reconcileStatus(ctx context.Context, store *openfgav1alpha1.Store) error {
+// reconcileDrift verifies the store still exists in OpenFGA.
+// A store that was deleted out-of-band is recreated or flagged according to the drift policy.
+func (r *StoreReconciler) reconcileDrift(ctx context.Context, store openfgav1alpha1.GenericStore) error {
+	log := log.FromContext(ctx)
+
+	fgaClient, err := r.FGA.ForStore(ctx, store)
+	if err != nil {
+		return r.reconcileFailed(ctx, store, openfgav1alpha1.ReasonConnectionFailed, err)
+	}
+
+	_, err = fgaClient.GetStore(ctx, store.GetStatus().StoreID)
+	if err == nil {
+		return r.reconcileSynced(ctx, store)
+	}
+
+	if !fga.IsNotFound(err) {
+		return r.reconcileFailed(ctx, store, openfgav1alpha1.ReasonReadFailed, err)
+	}
+
+	policy := r.driftPolicy(store)
+
+	// flagged stores are only reported once
+	if !hasReason(store.GetStatus().Conditions, openfgav1alpha1.ConditionSynced, openfgav1alpha1.ReasonStoreMissing) {
+		driftTotal.WithLabelValues(string(r.kind), string(policy)).Inc()
+
+		log.Info("store deleted in OpenFGA", "name", store.GetName(), "namespace", store.GetNamespace(), "id", store.GetStatus().StoreID, "policy", policy)
+		r.Recorder.Event(store, corev1.EventTypeWarning, cast.String(EventReasonStoreMissing), "store "+store.GetStatus().StoreID+" deleted in OpenFGA")
+	}
+
+	if policy == openfgav1alpha1.DriftPolicyFlag {
+		// the store is compared again with the next resync
+		r.setFailed(store, openfgav1alpha1.ReasonStoreMissing, fmt.Errorf("store %s does not exist in OpenFGA", store.GetStatus().StoreID))
+		return r.Status().Update(ctx, store)
+	}
+
+	s, err := fgaClient.CreateStore(ctx, store.GetName())
+	if err != nil {
+		r.Recorder.Event(store, corev1.EventTypeWarning, cast.String(EventReasonStoreCreateFailed), "store create failed")
+
+		return r.reconcileFailed(ctx, store, openfgav1alpha1.ReasonCreateFailed, err)
+	}
+
+	// models and tuples are written to the new store with their next resync
+	store.GetStatus().StoreID = s.ID
+	store.GetStatus().Adopted = false
+	store.GetStatus().Phase = openfgav1alpha1.StorePhaseSynchronized
+	r.setSynced(store)
+	err = r.Status().Update(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	r.Recorder.Event(store, corev1.EventTypeNormal, cast.String(EventReasonStoreRecreated), "store recreated as "+s.ID)
+
+	return nil
+}
+
+// driftPolicy returns the drift policy of the store.
+// Adopted stores were not created by the operator and are flagged unless the policy is set.
+func (r *StoreReconciler) driftPolicy(store openfgav1alpha1.GenericStore) openfgav1alpha1.DriftPolicy {
+	if utilx.Empty(store.GetSpec().DriftPolicy) && store.GetStatus().Adopted {
+		return openfgav1alpha1.DriftPolicyFlag
+	}
+
+	return r.FGA.DriftPolicy(store.GetSpec().DriftPolicy)
+}
+
+// reconcileSynced updates the conditions of a synchronized store.
+func (r *StoreReconciler) reconcileSynced(ctx context.Context, store openfgav1alpha1.GenericStore) error {
+	status := store.GetStatus().DeepCopy()
+
+	r.setSynced(store)
+	if equality.Semantic.DeepEqual(*status, *store.GetStatus()) {
+		return nil
+	}
+
+	return r.Status().Update(ctx, store)
+}
+
+// reconcileFailed records the error in the status of the store and returns it.
+func (r *StoreReconciler) reconcileFailed(ctx context.Context, store openfgav1alpha1.GenericStore, reason string, err error) error {
+	r.setFailed(store, reason, err)
+
+	if err := r.Status().Update(ctx, store); err != nil {
+		return err
+	}
+
+	return err
+}
+
+func (r *StoreReconciler) setFailed(store openfgav1alpha1.GenericStore, reason string, err error) {
+	store.GetStatus().Phase = openfgav1alpha1.StorePhaseFailed
+	store.GetStatus().ObservedGeneration = store.GetGeneration()
+	store.GetStatus().LastError = err.Error()
+	setCondition(&store.GetStatus().Conditions, store.GetGeneration(), openfgav1alpha1.ConditionSynced, metav1.ConditionFalse, reason, err.Error())
+	setReady(&store.GetStatus().Conditions, store.GetGeneration(), openfgav1alpha1.ConditionSynced)
+}
+
+func (r *StoreReconciler) setSynced(store openfgav1alpha1.GenericStore) {
+	store.GetStatus().ObservedGeneration = store.GetGeneration()
+	store.GetStatus().LastError = ""
+	setCondition(&store.GetStatus().Conditions, store.GetGeneration(), openfgav1alpha1.ConditionSynced, metav1.ConditionTrue, openfgav1alpha1.ReasonSynchronized, "store is synchronized")
+	setReady(&store.GetStatus().Conditions, store.GetGeneration(), openfgav1alpha1.ConditionSynced)
+}
+
+// adoptStore returns the existing store referenced by the spec.
+// It returns nil if there is no store to adopt and a new store should be created.
+func (r *StoreReconciler) adoptStore(ctx context.Context, fgaClient *fga.Client, store openfgav1alpha1.GenericStore) (*fga.Store, bool, error) {
 	log := log.FromContext(ctx)
-	log.Info("reconcile status", "name", store.Name, "namespace", store.Namespace)
+
+	id := store.GetSpec().StoreRef
+
+	if utilx.Empty(id) && store.GetSpec().AdoptByName {
+		stores, err := fgaClient.ListStores(ctx, store.GetName())
+		if err != nil {
+			return nil, false, err
+		}
+
+		if len(stores) > 1 {
+			return nil, false, fmt.Errorf("found %d stores with name %s", len(stores), store.GetName())
+		}
+
+		if len(stores) == 1 {
+			id = stores[0].ID
+		}
+	}
+
+	if utilx.Empty(id) {
+		return nil, false, nil
+	}
+
+	log.Info("adopt store", "name", store.GetName(), "namespace", store.GetNamespace(), "id", id)
+
+	s, err := fgaClient.GetStore(ctx, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return s, true, nil
+}
+
+func (r *StoreReconciler) reconcileStatus(ctx context.Context, store openfgav1alpha1.GenericStore) error {
+	log := log.FromContext(ctx)
+	log.Info("reconcile status", "name", store.GetName(), "namespace", store.GetNamespace())
 
 	phase := openfgav1alpha1.StorePhaseNone
 
-	if utilx.Empty(store.Status.StoreID) {
+	if utilx.Empty(store.GetStatus().StoreID) {
 		phase = openfgav1alpha1.StorePhaseCreating
 	}
 
-	if utilx.NotEmpty(store.Status.StoreID) {
+	if utilx.NotEmpty(store.GetStatus().StoreID) {
 		phase = openfgav1alpha1.StorePhaseSynchronized
 	}
 
-	if store.Status.Phase != phase {
-		store.Status.Phase = phase
+	if store.GetStatus().Phase != phase {
+		store.GetStatus().Phase = phase
 
 		return r.Status().Update(ctx, store)
 	}
@@ -183,21 +465,123 @@ func (r *StoreReconciler) reconcileStatus(ctx context.Context, store *openfgav1a
 	return nil
 }
 
-func (r *StoreReconciler) reconcileDelete(ctx context.Context, s *openfgav1alpha1.Store) error {
+func (r *StoreReconciler) reconcileDelete(ctx context.Context, store openfgav1alpha1.GenericStore) error {
 	log := log.FromContext(ctx)
 
-	log.Info("reconcile delete store", "name", s.Name, "namespace", s.Namespace)
+	log.Info("reconcile delete store", "name", store.GetName(), "namespace", store.GetNamespace())
 
-	err := r.FGA.DeleteStore(ctx, s.Status.StoreID)
-	if err != nil {
-		return err
+	policy := r.deletionPolicy(store)
+
+	if policy == openfgav1alpha1.DeletionPolicyOrphan {
+		err := r.orphanDependents(ctx, store)
+		if err != nil {
+			return err
+		}
+	}
+
+	if policy == openfgav1alpha1.DeletionPolicyDelete && utilx.NotEmpty(store.GetStatus().StoreID) {
+		fgaClient, err := r.FGA.ForStore(ctx, store)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+
+		// the server is gone, there is nothing left to delete
+		if fgaClient != nil {
+			err = fgaClient.DeleteStore(ctx, store.GetStatus().StoreID)
+			if err != nil && !fga.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	if policy != openfgav1alpha1.DeletionPolicyDelete {
+		r.Recorder.Event(store, corev1.EventTypeNormal, cast.String(EventReasonStoreRetained), "store retained in OpenFGA")
 	}
 
-	s.SetFinalizers(finalizers.RemoveFinalizer(s, openfgav1alpha1.FinalizerName))
-	err = r.Update(ctx, s)
+	store.SetFinalizers(finalizers.RemoveFinalizer(store, openfgav1alpha1.FinalizerName))
+	err := r.Update(ctx, store)
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
 
 	return nil
 }
+
+// deletionPolicy returns the deletion policy of the store.
+// Adopted stores were not created by the operator and are retained unless the policy is set.
+func (r *StoreReconciler) deletionPolicy(store openfgav1alpha1.GenericStore) openfgav1alpha1.DeletionPolicy {
+	if utilx.Empty(store.GetSpec().DeletionPolicy) && store.GetStatus().Adopted {
+		return openfgav1alpha1.DeletionPolicyRetain
+	}
+
+	return r.FGA.DeletionPolicy(store.GetSpec().DeletionPolicy)
+}
+
+// orphanDependents removes the owner references to the store,
+// so that the models and tuples of the store are not garbage collected.
+func (r *StoreReconciler) orphanDependents(ctx context.Context, store openfgav1alpha1.GenericStore) error {
+	log := log.FromContext(ctx)
+
+	models := &openfgav1alpha1.ModelList{}
+	tuples := &openfgav1alpha1.TupleList{}
+	sets := &openfgav1alpha1.TupleSetList{}
+
+	dependents := []client.Object{}
+
+	if err := r.List(ctx, models, client.InNamespace(store.GetNamespace())); err != nil {
+		return err
+	}
+	for i := range models.Items {
+		dependents = append(dependents, &models.Items[i])
+	}
+
+	if err := r.List(ctx, tuples, client.InNamespace(store.GetNamespace())); err != nil {
+		return err
+	}
+	for i := range tuples.Items {
+		dependents = append(dependents, &tuples.Items[i])
+	}
+
+	if err := r.List(ctx, sets, client.InNamespace(store.GetNamespace())); err != nil {
+		return err
+	}
+	for i := range sets.Items {
+		dependents = append(dependents, &sets.Items[i])
+	}
+
+	// cluster stores are referenced from all namespaces and by cluster models
+	if utilx.Empty(store.GetNamespace()) {
+		clusterModels := &openfgav1alpha1.ClusterModelList{}
+		if err := r.List(ctx, clusterModels); err != nil {
+			return err
+		}
+		for i := range clusterModels.Items {
+			dependents = append(dependents, &clusterModels.Items[i])
+		}
+	}
+
+	for _, obj := range dependents {
+		owned, err := controllerutil.HasOwnerReference(obj.GetOwnerReferences(), store, r.Scheme)
+		if err != nil {
+			return err
+		}
+
+		if !owned {
+			continue
+		}
+
+		log.Info("orphan dependent", "name", obj.GetName(), "namespace", obj.GetNamespace())
+
+		err = controllerutil.RemoveOwnerReference(store, obj, r.Scheme)
+		if err != nil {
+			return err
+		}
+
+		err = r.Update(ctx, obj)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
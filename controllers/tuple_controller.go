@@ -0,0 +1,365 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	openfgav1alpha1 "github.com/zeiss/openfga-operator/api/v1alpha1"
+	fga "github.com/zeiss/openfga-operator/pkg/client"
+	"github.com/zeiss/pkg/cast"
+	"github.com/zeiss/pkg/k8s/finalizers"
+	"github.com/zeiss/pkg/utilx"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	EventReasonTupleWritten EventReason = "TupleWritten"
+	EventReasonTupleDeleted EventReason = "TupleDeleted"
+	EventReasonTupleFailed  EventReason = "TupleFailed"
+)
+
+// TupleReconciler ...
+type TupleReconciler struct {
+	client.Client
+	Clock
+	FGA      *Clients
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// NewTupleReconciler ...
+func NewTupleReconciler(fga *Clients, mgr ctrl.Manager) *TupleReconciler {
+	return &TupleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor(EventRecorderLabel),
+		FGA:      fga,
+	}
+}
+
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=tuples,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=tuples/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=tuples/finalizers,verbs=update
+
+// Reconcile ...
+func (r *TupleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	log.Info("reconcile tuple", "name", req.Name, "namespace", req.Namespace)
+
+	tuple := &openfgav1alpha1.Tuple{}
+	if err := r.Get(ctx, req.NamespacedName, tuple); err != nil {
+		log.Error(err, "tuple not found", "tuple", req.NamespacedName)
+		// Request object not found, could have been deleted after reconcile request.
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	paused, err := reconcilePaused(ctx, r.Client, r.Recorder, tuple, tuple.Spec.Paused, &tuple.Status.ControlPaused)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if paused {
+		log.Info("tuple is paused", "name", tuple.Name, "namespace", tuple.Namespace)
+		return reconcile.Result{}, nil
+	}
+
+	if !tuple.ObjectMeta.DeletionTimestamp.IsZero() {
+		if finalizers.HasFinalizer(tuple, openfgav1alpha1.FinalizerName) {
+			err := r.reconcileDelete(ctx, tuple)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		// Delete
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.reconcileResources(ctx, tuple); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return r.FGA.Resync(), nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TupleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&openfgav1alpha1.Tuple{}).
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
+		Complete(r)
+}
+
+func (r *TupleReconciler) reconcileResources(ctx context.Context, tuple *openfgav1alpha1.Tuple) error {
+	log := log.FromContext(ctx)
+
+	err := r.reconcileStatus(ctx, tuple)
+	if err != nil {
+		log.Error(err, "failed to reconcile status", "name", tuple.Name, "namespace", tuple.Namespace)
+		return err
+	}
+
+	err = r.reconcileTuple(ctx, tuple)
+	if err != nil {
+		log.Error(err, "failed to reconcile tuple", "name", tuple.Name, "namespace", tuple.Namespace)
+		return err
+	}
+
+	return nil
+}
+
+func (r *TupleReconciler) reconcileTuple(ctx context.Context, tuple *openfgav1alpha1.Tuple) error {
+	log := log.FromContext(ctx)
+
+	log.Info("reconcile tuple", "name", tuple.Name, "namespace", tuple.Namespace)
+
+	store, err := FetchStore(ctx, r.Client, tuple.Namespace, tuple.Spec.StoreRef)
+	if IsNotGranted(err) {
+		r.Recorder.Event(tuple, corev1.EventTypeWarning, cast.String(EventReasonTupleFailed), "store "+StoreKey(tuple.Namespace, tuple.Spec.StoreRef).String()+" is not granted")
+
+		tuple.Status.Phase = openfgav1alpha1.TuplePhaseFailed
+		if err := r.Status().Update(ctx, tuple); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if utilx.Empty(store.GetStatus().StoreID) {
+		return fmt.Errorf("store %s is not synchronized", store.GetName())
+	}
+
+	fgaClient, err := r.FGA.ForStore(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	// the finalizer is added before the tuple is written, so that the written tuple is always deleted
+	err = r.reconcileOwner(ctx, store, tuple)
+	if err != nil {
+		return err
+	}
+
+	current := tuple.Status.Key != nil && tuple.Status.StoreID == store.GetStatus().StoreID && equality.Semantic.DeepEqual(*tuple.Status.Key, tuple.Spec.TupleKey)
+	pending := tuple.Status.Phase == openfgav1alpha1.TuplePhasePending
+
+	if current && !pending {
+		return nil
+	}
+
+	// the tuple has changed, remove the previously written tuple first
+	if tuple.Status.Key != nil && utilx.NotEmpty(tuple.Status.StoreID) && !current {
+		old, err := toTuple(*tuple.Status.Key)
+		if err != nil {
+			return err
+		}
+
+		// a tuple written to a previous store is removed with the client of the server of that store
+		oldClient := fgaClient
+		if tuple.Status.StoreID != store.GetStatus().StoreID {
+			oldClient, err = r.FGA.ForDeletion(ctx, tuple.Namespace, writtenStoreRef(tuple.Status.StoreRef, tuple.Spec.StoreRef))
+			if err != nil {
+				return err
+			}
+		}
+
+		// the store may already be gone together with all of its tuples
+		if oldClient != nil {
+			err = oldClient.DeleteTuple(ctx, tuple.Status.StoreID, old)
+			if err != nil && !fga.IsNotFound(err) {
+				return err
+			}
+		}
+
+		tuple.Status.Key = nil
+	}
+
+	t, err := toTuple(tuple.Spec.TupleKey)
+	if err != nil {
+		return err
+	}
+
+	exists, err := fgaClient.HasTuple(ctx, store.GetStatus().StoreID, t)
+	if err != nil {
+		return err
+	}
+
+	// a tuple written by others is neither overwritten nor owned, so it is not deleted together with the tuple
+	if exists && !current {
+		r.Recorder.Event(tuple, corev1.EventTypeWarning, cast.String(EventReasonTupleFailed), "tuple already exists in the store and is not owned")
+
+		tuple.Status.StoreID = store.GetStatus().StoreID
+		tuple.Status.StoreRef = tuple.Spec.StoreRef.DeepCopy()
+		tuple.Status.Phase = openfgav1alpha1.TuplePhaseFailed
+
+		return r.Status().Update(ctx, tuple)
+	}
+
+	// the tuple is recorded as pending before it is written, so that it is deleted even if recording it as written fails.
+	// A pending tuple that exists in the store has been written by a previous reconcile.
+	if !current {
+		tuple.Status.StoreID = store.GetStatus().StoreID
+		tuple.Status.StoreRef = tuple.Spec.StoreRef.DeepCopy()
+		tuple.Status.Key = tuple.Spec.TupleKey.DeepCopy()
+		tuple.Status.Phase = openfgav1alpha1.TuplePhasePending
+		err = r.Status().Update(ctx, tuple)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !exists {
+		log.Info("write tuple to store", "name", store.GetName(), "namespace", store.GetNamespace())
+
+		err = fgaClient.WriteTuple(ctx, store.GetStatus().StoreID, t)
+		if err != nil {
+			log.Error(err, "failed to write tuple", "name", tuple.Name, "namespace", tuple.Namespace)
+			r.Recorder.Event(tuple, corev1.EventTypeWarning, cast.String(EventReasonTupleFailed), "tuple write failed")
+
+			// the tuple stays pending and is written again
+			return err
+		}
+	}
+
+	tuple.Status.Phase = openfgav1alpha1.TuplePhaseSynchronized
+	err = r.Status().Update(ctx, tuple)
+	if err != nil {
+		return err
+	}
+
+	r.Recorder.Event(tuple, corev1.EventTypeNormal, cast.String(EventReasonTupleWritten), "tuple written")
+
+	return nil
+}
+
+// reconcileOwner adds the finalizer and sets the store as the owner of the tuple, if the store can own it.
+func (r *TupleReconciler) reconcileOwner(ctx context.Context, store openfgav1alpha1.GenericStore, tuple *openfgav1alpha1.Tuple) error {
+	refs, fins := len(tuple.OwnerReferences), len(tuple.Finalizers)
+
+	if canOwn(store, tuple) {
+		err := controllerutil.SetOwnerReference(store, tuple, r.Scheme)
+		if err != nil {
+			return err
+		}
+	}
+
+	tuple.Finalizers = finalizers.AddFinalizer(tuple, openfgav1alpha1.FinalizerName)
+	if refs == len(tuple.OwnerReferences) && fins == len(tuple.Finalizers) {
+		return nil
+	}
+
+	return r.Update(ctx, tuple)
+}
+
+func (r *TupleReconciler) reconcileStatus(ctx context.Context, tuple *openfgav1alpha1.Tuple) error {
+	log := log.FromContext(ctx)
+
+	log.Info("change status", "name", tuple.Name, "namespace", tuple.Namespace)
+
+	phase := openfgav1alpha1.TuplePhaseNone
+
+	if tuple.Status.Key == nil {
+		phase = openfgav1alpha1.TuplePhaseCreating
+	}
+
+	if tuple.Status.Key != nil {
+		phase = openfgav1alpha1.TuplePhaseSynchronized
+	}
+
+	// a recorded tuple stays pending until it is written
+	if tuple.Status.Key != nil && tuple.Status.Phase == openfgav1alpha1.TuplePhasePending {
+		phase = openfgav1alpha1.TuplePhasePending
+	}
+
+	if tuple.Status.Phase != phase {
+		tuple.Status.Phase = phase
+
+		return r.Status().Update(ctx, tuple)
+	}
+
+	return nil
+}
+
+func (r *TupleReconciler) reconcileDelete(ctx context.Context, tuple *openfgav1alpha1.Tuple) error {
+	log := log.FromContext(ctx)
+
+	log.Info("delete tuple", "name", tuple.Name, "namespace", tuple.Namespace)
+
+	fgaClient, err := r.FGA.ForDeletion(ctx, tuple.Namespace, writtenStoreRef(tuple.Status.StoreRef, tuple.Spec.StoreRef))
+	if err != nil {
+		return err
+	}
+
+	if fgaClient != nil && tuple.Status.Key != nil && utilx.NotEmpty(tuple.Status.StoreID) {
+		t, err := toTuple(*tuple.Status.Key)
+		if err != nil {
+			return err
+		}
+
+		// the store may already be gone together with all of its tuples
+		err = fgaClient.DeleteTuple(ctx, tuple.Status.StoreID, t)
+		if err != nil && !fga.IsNotFound(err) {
+			return err
+		}
+
+		r.Recorder.Event(tuple, corev1.EventTypeNormal, cast.String(EventReasonTupleDeleted), "tuple deleted")
+	}
+
+	tuple.SetFinalizers(finalizers.RemoveFinalizer(tuple, openfgav1alpha1.FinalizerName))
+	err = r.Update(ctx, tuple)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// writtenStoreRef returns the reference to the store the tuples have been written to,
+// which is the reference of the spec if it has not been recorded.
+func writtenStoreRef(written *openfgav1alpha1.StoreRef, ref openfgav1alpha1.StoreRef) openfgav1alpha1.StoreRef {
+	if written == nil {
+		return ref
+	}
+
+	return *written
+}
+
+func toTuple(key openfgav1alpha1.TupleKey) (fga.Tuple, error) {
+	t := fga.Tuple{
+		User:     key.User,
+		Relation: key.Relation,
+		Object:   key.Object,
+	}
+
+	if key.Condition == nil {
+		return t, nil
+	}
+
+	t.Condition = &fga.Condition{
+		Name: key.Condition.Name,
+	}
+
+	values, err := toContext(key.Condition.Context)
+	if err != nil {
+		return t, err
+	}
+	t.Condition.Context = values
+
+	return t, nil
+}
@@ -0,0 +1,481 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	openfgav1alpha1 "github.com/zeiss/openfga-operator/api/v1alpha1"
+	fga "github.com/zeiss/openfga-operator/pkg/client"
+	"github.com/zeiss/pkg/cast"
+	"github.com/zeiss/pkg/k8s/finalizers"
+	"github.com/zeiss/pkg/utilx"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	EventReasonTupleSetSynchronized EventReason = "TupleSetSynchronized"
+	EventReasonTupleSetDeleted      EventReason = "TupleSetDeleted"
+	EventReasonTupleSetFailed       EventReason = "TupleSetFailed"
+)
+
+// TupleSetReconciler ...
+type TupleSetReconciler struct {
+	client.Client
+	Clock
+	FGA      *Clients
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// NewTupleSetReconciler ...
+func NewTupleSetReconciler(fga *Clients, mgr ctrl.Manager) *TupleSetReconciler {
+	return &TupleSetReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor(EventRecorderLabel),
+		FGA:      fga,
+	}
+}
+
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=tuplesets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=tuplesets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=tuplesets/finalizers,verbs=update
+
+// Reconcile ...
+func (r *TupleSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	log.Info("reconcile tuple set", "name", req.Name, "namespace", req.Namespace)
+
+	set := &openfgav1alpha1.TupleSet{}
+	if err := r.Get(ctx, req.NamespacedName, set); err != nil {
+		log.Error(err, "tuple set not found", "tupleset", req.NamespacedName)
+		// Request object not found, could have been deleted after reconcile request.
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	paused, err := reconcilePaused(ctx, r.Client, r.Recorder, set, set.Spec.Paused, &set.Status.ControlPaused)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if paused {
+		log.Info("tuple set is paused", "name", set.Name, "namespace", set.Namespace)
+		return reconcile.Result{}, nil
+	}
+
+	if !set.ObjectMeta.DeletionTimestamp.IsZero() {
+		if finalizers.HasFinalizer(set, openfgav1alpha1.FinalizerName) {
+			err := r.reconcileDelete(ctx, set)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		// Delete
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.reconcileResources(ctx, set); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return r.FGA.Resync(), nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TupleSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&openfgav1alpha1.TupleSet{}).
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
+		Complete(r)
+}
+
+func (r *TupleSetReconciler) reconcileResources(ctx context.Context, set *openfgav1alpha1.TupleSet) error {
+	log := log.FromContext(ctx)
+
+	err := r.reconcileStatus(ctx, set)
+	if err != nil {
+		log.Error(err, "failed to reconcile status", "name", set.Name, "namespace", set.Namespace)
+		return err
+	}
+
+	err = r.reconcileTuples(ctx, set)
+	if err != nil {
+		log.Error(err, "failed to reconcile tuples", "name", set.Name, "namespace", set.Namespace)
+		return err
+	}
+
+	return nil
+}
+
+func (r *TupleSetReconciler) reconcileTuples(ctx context.Context, set *openfgav1alpha1.TupleSet) error {
+	log := log.FromContext(ctx)
+
+	log.Info("reconcile tuples", "name", set.Name, "namespace", set.Namespace)
+
+	store, err := FetchStore(ctx, r.Client, set.Namespace, set.Spec.StoreRef)
+	if IsNotGranted(err) {
+		r.Recorder.Event(set, corev1.EventTypeWarning, cast.String(EventReasonTupleSetFailed), "store "+StoreKey(set.Namespace, set.Spec.StoreRef).String()+" is not granted")
+
+		set.Status.Phase = openfgav1alpha1.TupleSetPhaseFailed
+		if err := r.Status().Update(ctx, set); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if utilx.Empty(store.GetStatus().StoreID) {
+		return fmt.Errorf("store %s is not synchronized", store.GetName())
+	}
+
+	fgaClient, err := r.FGA.ForStore(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	// the finalizer is added before any tuple is written, so that written tuples are always pruned
+	err = r.reconcileOwner(ctx, store, set)
+	if err != nil {
+		return err
+	}
+
+	desired := uniqueTuples(set.Spec.Tuples)
+
+	// tuples owned in a previous store are pruned from that store with the client of the server of that store
+	if utilx.NotEmpty(set.Status.StoreID) && set.Status.StoreID != store.GetStatus().StoreID {
+		oldClient, err := r.FGA.ForDeletion(ctx, set.Namespace, writtenStoreRef(set.Status.StoreRef, set.Spec.StoreRef))
+		if err != nil {
+			return err
+		}
+
+		// the store may already be gone together with all of its tuples
+		if oldClient != nil {
+			err = deleteTuples(ctx, oldClient, set.Status.StoreID, set.Status.Tuples)
+			if err != nil && !fga.IsNotFound(err) {
+				return err
+			}
+		}
+
+		set.Status.Tuples = nil
+	}
+
+	writes, deletes := diffTuples(set.Status.Tuples, desired)
+
+	missing, err := missingTuples(ctx, fgaClient, store.GetStatus().StoreID, writes, deletes)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) < len(writes) {
+		log.Info("tuples already exist in the store and are not owned", "name", set.Name, "namespace", set.Namespace, "count", len(writes)-len(missing))
+	}
+
+	writes = missing
+
+	if len(writes) == 0 && len(deletes) == 0 && set.Status.StoreID == store.GetStatus().StoreID && set.Status.StoreRef != nil && *set.Status.StoreRef == set.Spec.StoreRef {
+		return nil
+	}
+
+	log.Info("synchronize tuples with store", "name", store.GetName(), "namespace", store.GetNamespace(), "writes", len(writes), "deletes", len(deletes))
+
+	owned := make(map[string]openfgav1alpha1.TupleKey, len(set.Status.Tuples))
+	for _, key := range set.Status.Tuples {
+		owned[key.String()] = key
+	}
+
+	err = syncTuples(ctx, fgaClient, store.GetStatus().StoreID, writes, deletes, owned)
+	if err != nil {
+		log.Error(err, "failed to synchronize tuples", "name", set.Name, "namespace", set.Namespace)
+		r.Recorder.Event(set, corev1.EventTypeWarning, cast.String(EventReasonTupleSetFailed), "tuple set synchronization failed")
+
+		// keep track of the batches that have been applied to prune them later
+		set.Status.StoreID = store.GetStatus().StoreID
+		set.Status.StoreRef = set.Spec.StoreRef.DeepCopy()
+		set.Status.Tuples = sortedTuples(owned)
+		set.Status.Phase = openfgav1alpha1.TupleSetPhaseFailed
+		if err := r.updateStatus(ctx, set); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	set.Status.StoreID = store.GetStatus().StoreID
+	set.Status.StoreRef = set.Spec.StoreRef.DeepCopy()
+	set.Status.Tuples = sortedTuples(owned)
+	set.Status.Phase = openfgav1alpha1.TupleSetPhaseSynchronized
+	err = r.updateStatus(ctx, set)
+	if err != nil {
+		return err
+	}
+
+	r.Recorder.Event(set, corev1.EventTypeNormal, cast.String(EventReasonTupleSetSynchronized), fmt.Sprintf("tuple set synchronized (%d written, %d deleted)", len(writes), len(deletes)))
+
+	return nil
+}
+
+// reconcileOwner adds the finalizer and sets the store as the owner of the tuple set, if the store can own it.
+func (r *TupleSetReconciler) reconcileOwner(ctx context.Context, store openfgav1alpha1.GenericStore, set *openfgav1alpha1.TupleSet) error {
+	refs, fins := len(set.OwnerReferences), len(set.Finalizers)
+
+	if canOwn(store, set) {
+		err := controllerutil.SetOwnerReference(store, set, r.Scheme)
+		if err != nil {
+			return err
+		}
+	}
+
+	set.Finalizers = finalizers.AddFinalizer(set, openfgav1alpha1.FinalizerName)
+	if refs == len(set.OwnerReferences) && fins == len(set.Finalizers) {
+		return nil
+	}
+
+	return r.Update(ctx, set)
+}
+
+// updateStatus updates the status of the tuple set and retries on conflicts with the latest tuple set,
+// as tuples that are written but not recorded as owned would never be pruned.
+func (r *TupleSetReconciler) updateStatus(ctx context.Context, set *openfgav1alpha1.TupleSet) error {
+	status := set.Status.DeepCopy()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := r.Status().Update(ctx, set)
+		if !errors.IsConflict(err) {
+			return err
+		}
+
+		if err := r.Get(ctx, client.ObjectKeyFromObject(set), set); err != nil {
+			return err
+		}
+
+		set.Status = *status
+
+		return err
+	})
+}
+
+func (r *TupleSetReconciler) reconcileStatus(ctx context.Context, set *openfgav1alpha1.TupleSet) error {
+	log := log.FromContext(ctx)
+
+	log.Info("change status", "name", set.Name, "namespace", set.Namespace)
+
+	phase := openfgav1alpha1.TupleSetPhaseNone
+
+	if utilx.Empty(set.Status.StoreID) {
+		phase = openfgav1alpha1.TupleSetPhaseCreating
+	}
+
+	if utilx.NotEmpty(set.Status.StoreID) {
+		phase = openfgav1alpha1.TupleSetPhaseSynchronized
+	}
+
+	if set.Status.Phase != phase {
+		set.Status.Phase = phase
+
+		return r.Status().Update(ctx, set)
+	}
+
+	return nil
+}
+
+func (r *TupleSetReconciler) reconcileDelete(ctx context.Context, set *openfgav1alpha1.TupleSet) error {
+	log := log.FromContext(ctx)
+
+	log.Info("delete tuple set", "name", set.Name, "namespace", set.Namespace)
+
+	fgaClient, err := r.FGA.ForDeletion(ctx, set.Namespace, writtenStoreRef(set.Status.StoreRef, set.Spec.StoreRef))
+	if err != nil {
+		return err
+	}
+
+	if fgaClient != nil && utilx.NotEmpty(set.Status.StoreID) && len(set.Status.Tuples) > 0 {
+		// the store may already be gone together with all of its tuples
+		err := deleteTuples(ctx, fgaClient, set.Status.StoreID, set.Status.Tuples)
+		if err != nil && !fga.IsNotFound(err) {
+			return err
+		}
+
+		r.Recorder.Event(set, corev1.EventTypeNormal, cast.String(EventReasonTupleSetDeleted), "tuple set deleted")
+	}
+
+	set.SetFinalizers(finalizers.RemoveFinalizer(set, openfgav1alpha1.FinalizerName))
+	err = r.Update(ctx, set)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// syncTuples applies the deletes and writes in batches and records the applied batches in owned.
+func syncTuples(ctx context.Context, fgaClient *fga.Client, store string, writes, deletes []openfgav1alpha1.TupleKey, owned map[string]openfgav1alpha1.TupleKey) error {
+	for batch := range slices.Chunk(deletes, fga.MaxTuplesPerWrite) {
+		err := deleteTuples(ctx, fgaClient, store, batch)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range batch {
+			delete(owned, key.String())
+		}
+	}
+
+	for batch := range slices.Chunk(writes, fga.MaxTuplesPerWrite) {
+		err := writeTuples(ctx, fgaClient, store, batch)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range batch {
+			owned[key.String()] = key
+		}
+	}
+
+	return nil
+}
+
+// missingTuples returns the tuples to write that do not exist in the store yet.
+// Existing tuples have been written by others and are neither written nor owned,
+// except for the tuples that are deleted before to write them with a changed condition.
+func missingTuples(ctx context.Context, fgaClient *fga.Client, store string, writes, deletes []openfgav1alpha1.TupleKey) ([]openfgav1alpha1.TupleKey, error) {
+	replaced := make(map[string]struct{}, len(deletes))
+	for _, key := range deletes {
+		replaced[key.String()] = struct{}{}
+	}
+
+	missing := make([]openfgav1alpha1.TupleKey, 0, len(writes))
+	for _, key := range writes {
+		if _, ok := replaced[key.String()]; ok {
+			missing = append(missing, key)
+			continue
+		}
+
+		t, err := toTuple(key)
+		if err != nil {
+			return nil, err
+		}
+
+		exists, err := fgaClient.HasTuple(ctx, store, t)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			missing = append(missing, key)
+		}
+	}
+
+	return missing, nil
+}
+
+// sortedTuples returns the owned tuples sorted by their notation.
+func sortedTuples(owned map[string]openfgav1alpha1.TupleKey) []openfgav1alpha1.TupleKey {
+	return slices.SortedFunc(maps.Values(owned), func(a, b openfgav1alpha1.TupleKey) int {
+		return strings.Compare(a.String(), b.String())
+	})
+}
+
+func writeTuples(ctx context.Context, fgaClient *fga.Client, store string, keys []openfgav1alpha1.TupleKey) error {
+	tuples, err := toTuples(keys)
+	if err != nil {
+		return err
+	}
+
+	return fgaClient.WriteTuples(ctx, store, tuples)
+}
+
+func deleteTuples(ctx context.Context, fgaClient *fga.Client, store string, keys []openfgav1alpha1.TupleKey) error {
+	tuples, err := toTuples(keys)
+	if err != nil {
+		return err
+	}
+
+	return fgaClient.DeleteTuples(ctx, store, tuples)
+}
+
+func toTuples(keys []openfgav1alpha1.TupleKey) ([]fga.Tuple, error) {
+	tuples := make([]fga.Tuple, 0, len(keys))
+
+	for _, key := range keys {
+		t, err := toTuple(key)
+		if err != nil {
+			return nil, err
+		}
+
+		tuples = append(tuples, t)
+	}
+
+	return tuples, nil
+}
+
+// uniqueTuples removes duplicate tuples, the last occurrence of a tuple wins.
+func uniqueTuples(keys []openfgav1alpha1.TupleKey) []openfgav1alpha1.TupleKey {
+	index := make(map[string]int, len(keys))
+	unique := make([]openfgav1alpha1.TupleKey, 0, len(keys))
+
+	for _, key := range keys {
+		if i, ok := index[key.String()]; ok {
+			unique[i] = key
+			continue
+		}
+
+		index[key.String()] = len(unique)
+		unique = append(unique, key)
+	}
+
+	return unique
+}
+
+// diffTuples returns the tuples that have to be written and deleted to get from the owned to the desired tuples.
+// Tuples with a changed condition are deleted and written again.
+func diffTuples(owned, desired []openfgav1alpha1.TupleKey) (writes, deletes []openfgav1alpha1.TupleKey) {
+	current := make(map[string]openfgav1alpha1.TupleKey, len(owned))
+	for _, key := range owned {
+		current[key.String()] = key
+	}
+
+	wanted := make(map[string]struct{}, len(desired))
+	for _, key := range desired {
+		wanted[key.String()] = struct{}{}
+
+		c, ok := current[key.String()]
+		if !ok {
+			writes = append(writes, key)
+			continue
+		}
+
+		if !equality.Semantic.DeepEqual(c.Condition, key.Condition) {
+			deletes = append(deletes, c)
+			writes = append(writes, key)
+		}
+	}
+
+	for _, key := range owned {
+		if _, ok := wanted[key.String()]; !ok {
+			deletes = append(deletes, key)
+		}
+	}
+
+	return writes, deletes
+}
@@ -0,0 +1,471 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	openfgav1alpha1 "github.com/zeiss/openfga-operator/api/v1alpha1"
+	"github.com/zeiss/pkg/cast"
+	"github.com/zeiss/pkg/slices"
+	"github.com/zeiss/pkg/utilx"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	EventReasonWorkloadEnvUpdated EventReason = "WorkloadEnvUpdated"
+	EventReasonWorkloadImmutable  EventReason = "WorkloadImmutable"
+	EventReasonWorkloadEnvRemoved EventReason = "WorkloadEnvRemoved"
+	EventReasonWorkloadNotGranted EventReason = "WorkloadNotGranted"
+)
+
+const (
+	// ModelRefAnnotation references the model of a workload by its name, or by its namespace and name
+	// separated by a slash if a store grant permits it.
+	ModelRefAnnotation = ModelAnnotationPrefix + "ref"
+	// ModelKindAnnotation is the kind of the referenced model, it references a cluster model if set to ClusterModel.
+	ModelKindAnnotation = ModelAnnotationPrefix + "kind"
+	// ModelHashAnnotation is set on the pod template to the hash of the store and model IDs.
+	ModelHashAnnotation = ModelAnnotationPrefix + "hash"
+	// ModelInjectedAnnotation records the model and the env vars injected into a workload,
+	// so that they can be removed when the reference is dropped or changed.
+	ModelInjectedAnnotation = ModelAnnotationPrefix + "injected"
+)
+
+// ModelKindClusterModel is the value of the model.kind annotation referencing a cluster model.
+const ModelKindClusterModel = "ClusterModel"
+
+// injection is the model and the env vars injected into a workload.
+type injection struct {
+	// Model is the reference to the injected model.
+	Model string `json:"model"`
+	// Kind is the kind of the injected model, it is empty for models.
+	Kind string `json:"kind,omitempty"`
+	// Env are the names of the injected env vars.
+	Env []string `json:"env"`
+	// Containers are the names of the containers the env vars are injected into.
+	Containers []string `json:"containers,omitempty"`
+}
+
+// injectionOf returns the injection recorded on the workload, it is nil if nothing was injected.
+func injectionOf(obj client.Object) (*injection, error) {
+	v, ok := obj.GetAnnotations()[ModelInjectedAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	i := &injection{}
+	err := json.Unmarshal([]byte(v), i)
+	if err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+const workloadModelIndex = ".metadata.annotations.model.ref"
+
+// workload is a kind of resource with a pod template the model is injected into.
+type workload struct {
+	gvk schema.GroupVersionKind
+	// template is the path of the pod template in the resource.
+	template []string
+	// immutable workloads can not change their pod template once created.
+	immutable bool
+	// optional workloads are only reconciled if their custom resource is installed.
+	optional bool
+}
+
+// workloads are the kinds of resources the model is injected into.
+var workloads = []workload{
+	{gvk: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, template: []string{"spec", "template"}},
+	{gvk: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}, template: []string{"spec", "template"}},
+	{gvk: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"}, template: []string{"spec", "template"}},
+	{gvk: schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}, template: []string{"spec", "template"}, immutable: true},
+	{gvk: schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"}, template: []string{"spec", "jobTemplate", "spec", "template"}},
+	{gvk: schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"}, template: []string{"spec", "template"}, optional: true},
+}
+
+func (w workload) object() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(w.gvk)
+
+	return obj
+}
+
+func (w workload) list() *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(w.gvk.GroupVersion().WithKind(w.gvk.Kind + "List"))
+
+	return list
+}
+
+// podTemplate returns the pod template of the resource, it returns nil if the resource has none
+// (e.g. a rollout that references the template of a deployment).
+func (w workload) podTemplate(obj *unstructured.Unstructured) (*corev1.PodTemplateSpec, error) {
+	m, ok, err := unstructured.NestedMap(obj.Object, w.template...)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	template := &corev1.PodTemplateSpec{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(m, template)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (w workload) setPodTemplate(obj *unstructured.Unstructured, template *corev1.PodTemplateSpec) error {
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(template)
+	if err != nil {
+		return err
+	}
+
+	return unstructured.SetNestedMap(obj.Object, m, w.template...)
+}
+
+// PodReconciler injects the model and its store into the workloads that reference the model.
+type PodReconciler struct {
+	client.Client
+	Clock
+	FGA      *Clients
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// PodInjection skips the workloads of namespaces that are injected at admission (see InjectionLabel).
+	PodInjection bool
+}
+
+// NewPodReconciler ...
+func NewPodReconciler(fga *Clients, mgr ctrl.Manager) *PodReconciler {
+	return &PodReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor(EventRecorderLabel),
+		FGA:      fga,
+	}
+}
+
+//+kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=argoproj.io,resources=rollouts,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=models,verbs=get;list;watch
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=stores,verbs=get;list;watch
+//+kubebuilder:rbac:groups=openfga.zeiss.com,resources=clustermodels;clusterstores,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// SetupWithManager sets up a controller for each kind of workload with the Manager.
+func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	for _, w := range workloads {
+		if w.optional {
+			_, err := mgr.GetRESTMapper().RESTMapping(w.gvk.GroupKind(), w.gvk.Version)
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		err := (&workloadReconciler{PodReconciler: r, workload: w}).SetupWithManager(mgr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// workloadReconciler reconciles a kind of workload.
+type workloadReconciler struct {
+	*PodReconciler
+	workload
+}
+
+// Reconcile ...
+func (r *workloadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	log.Info("reconcile workload", "kind", r.gvk.Kind, "name", req.Name, "namespace", req.Namespace)
+
+	obj := r.object()
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		log.Error(err, "workload not found", "kind", r.gvk.Kind, "workload", req.NamespacedName)
+		// Request object not found, could have been deleted after reconcile request.
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !obj.GetDeletionTimestamp().IsZero() {
+		// the injected env vars are deleted together with the workload
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.reconcileResources(ctx, obj); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *workloadReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), r.object(), workloadModelIndex, func(obj client.Object) []string {
+		if _, ok := obj.GetAnnotations()[ModelRefAnnotation]; !ok {
+			return nil
+		}
+
+		return []string{ModelKey(obj.GetNamespace(), obj.GetAnnotations()).String()}
+	})
+	if err != nil {
+		return err
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named(strings.ToLower(r.gvk.Kind)).
+		For(r.object(), builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Watches(&openfgav1alpha1.Model{}, handler.EnqueueRequestsFromMapFunc(r.findWorkloadsForModel)).
+		Watches(&openfgav1alpha1.Store{}, handler.EnqueueRequestsFromMapFunc(r.findWorkloadsForStore)).
+		Watches(&openfgav1alpha1.ClusterModel{}, handler.EnqueueRequestsFromMapFunc(r.findWorkloadsForModel)).
+		Watches(&openfgav1alpha1.ClusterStore{}, handler.EnqueueRequestsFromMapFunc(r.findWorkloadsForStore))
+
+	// workloads are updated again when their namespace opts out of the injection at admission
+	if r.PodInjection {
+		b = b.Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.findWorkloadsForNamespace), builder.WithPredicates(predicate.LabelChangedPredicate{}))
+	}
+
+	return b.Complete(r)
+}
+
+func (r *workloadReconciler) reconcileResources(ctx context.Context, obj *unstructured.Unstructured) error {
+	log := log.FromContext(ctx)
+
+	log.Info("reconcile openfga workload", "kind", r.gvk.Kind, "name", obj.GetName(), "namespace", obj.GetNamespace())
+
+	// the models are injected into the pods of the namespace instead
+	if r.PodInjection {
+		ns := &corev1.Namespace{}
+		err := r.Get(ctx, client.ObjectKey{Name: obj.GetNamespace()}, ns)
+		if err != nil {
+			return err
+		}
+
+		if InjectedAtAdmission(ns) {
+			return nil
+		}
+	}
+
+	ref, ok := obj.GetAnnotations()[ModelRefAnnotation]
+	kind := obj.GetAnnotations()[ModelKindAnnotation]
+
+	injected, err := injectionOf(obj)
+	if err != nil {
+		return err
+	}
+
+	// the reference was dropped or points to a different model
+	if injected != nil && (!ok || injected.Model != ref || injected.Kind != kind) {
+		err = r.reconcileRemoved(ctx, obj, injected)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !ok {
+		return nil
+	}
+
+	model, err := FetchModel(ctx, r.Client, obj.GetNamespace(), obj.GetAnnotations())
+	if err != nil {
+		if IsNotGranted(err) {
+			r.Recorder.Event(obj, corev1.EventTypeWarning, cast.String(EventReasonWorkloadNotGranted), "model "+ModelKey(obj.GetNamespace(), obj.GetAnnotations()).String()+" is not granted")
+		}
+
+		return client.IgnoreNotFound(err)
+	}
+
+	// the store is referenced by the model, which is granted to the namespace of the model
+	store, err := FetchStore(ctx, r.Client, model.GetNamespace(), model.GetSpec().StoreRef)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	// the environment is set once the model is written to the store
+	if utilx.Empty(model.GetStatus().InstanceID) || utilx.Empty(store.GetStatus().StoreID) {
+		return nil
+	}
+
+	template, err := r.podTemplate(obj)
+	if err != nil || template == nil {
+		return err
+	}
+
+	env, err := ModelEnv(ctx, r.Client, r.FGA.DefaultURL(), obj.GetNamespace(), store, model, obj.GetAnnotations())
+	if err != nil {
+		return err
+	}
+
+	// the hash of the pod template rolls out the workload when the model, the store or the annotations change
+	b, err := json.Marshal(struct {
+		Env        []corev1.EnvVar
+		Containers string
+	}{env, obj.GetAnnotations()[ModelContainersAnnotation]})
+	if err != nil {
+		return err
+	}
+
+	hash := specHash(string(b))
+	if template.Annotations[ModelHashAnnotation] == hash {
+		return nil
+	}
+
+	if r.immutable {
+		r.Recorder.Event(obj, corev1.EventTypeWarning, cast.String(EventReasonWorkloadImmutable), "the pod template of a "+strings.ToLower(r.gvk.Kind)+" can not be updated with OpenFGA model instance "+model.GetStatus().InstanceID)
+		return nil
+	}
+
+	// the env vars may have been renamed or moved to other containers
+	if injected != nil && injected.Model == ref && injected.Kind == kind {
+		RemoveEnv(&template.Spec, injected.Env, injected.Containers)
+	}
+
+	containers := InjectEnv(&template.Spec, env, obj.GetAnnotations())
+
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[ModelHashAnnotation] = hash
+
+	err = r.setPodTemplate(obj, template)
+	if err != nil {
+		return err
+	}
+
+	b, err = json.Marshal(injection{Model: ref, Kind: kind, Env: slices.Map(func(v corev1.EnvVar) string { return v.Name }, env...), Containers: containers})
+	if err != nil {
+		return err
+	}
+
+	annotations := obj.GetAnnotations()
+	annotations[ModelUpdatedAnnotation] = time.Now().Format(time.RFC3339)
+	annotations[ModelInjectedAnnotation] = string(b)
+	obj.SetAnnotations(annotations)
+
+	if err := r.Update(ctx, obj); err != nil {
+		return err
+	}
+
+	r.Recorder.Event(obj, corev1.EventTypeNormal, cast.String(EventReasonWorkloadEnvUpdated), "OpenFGA model instance "+model.GetStatus().InstanceID+" added to the environment")
+
+	return nil
+}
+
+// reconcileRemoved removes the injected env vars and annotations from the workload.
+func (r *workloadReconciler) reconcileRemoved(ctx context.Context, obj *unstructured.Unstructured, injected *injection) error {
+	template, err := r.podTemplate(obj)
+	if err != nil {
+		return err
+	}
+
+	if template != nil {
+		RemoveEnv(&template.Spec, injected.Env, injected.Containers)
+		delete(template.Annotations, ModelHashAnnotation)
+
+		err = r.setPodTemplate(obj, template)
+		if err != nil {
+			return err
+		}
+	}
+
+	annotations := obj.GetAnnotations()
+	delete(annotations, ModelUpdatedAnnotation)
+	delete(annotations, ModelInjectedAnnotation)
+	obj.SetAnnotations(annotations)
+
+	if err := r.Update(ctx, obj); err != nil {
+		return err
+	}
+
+	r.Recorder.Event(obj, corev1.EventTypeNormal, cast.String(EventReasonWorkloadEnvRemoved), "OpenFGA model "+injected.Model+" removed from the environment")
+
+	return nil
+}
+
+// findWorkloadsForModel returns the workloads that reference the model, also from other namespaces.
+func (r *workloadReconciler) findWorkloadsForModel(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := r.list()
+	err := r.List(ctx, list, client.MatchingFields{workloadModelIndex: client.ObjectKeyFromObject(obj).String()})
+	if err != nil {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, item := range list.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&item)})
+	}
+
+	return requests
+}
+
+// findWorkloadsForNamespace returns the workloads of the namespace that reference a model.
+func (r *workloadReconciler) findWorkloadsForNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := r.list()
+	err := r.List(ctx, list, client.InNamespace(obj.GetName()))
+	if err != nil {
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, item := range list.Items {
+		if _, ok := item.GetAnnotations()[ModelRefAnnotation]; ok {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&item)})
+		}
+	}
+
+	return requests
+}
+
+// findWorkloadsForStore returns the workloads that reference a model of the store.
+// Models may reference the store from other namespaces.
+func (r *workloadReconciler) findWorkloadsForStore(ctx context.Context, obj client.Object) []reconcile.Request {
+	models := &openfgav1alpha1.ModelList{}
+	err := r.List(ctx, models)
+	if err != nil {
+		return nil
+	}
+
+	clusterModels := &openfgav1alpha1.ClusterModelList{}
+	err = r.List(ctx, clusterModels)
+	if err != nil {
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, model := range models.Items {
+		if StoreKey(model.Namespace, model.Spec.StoreRef) == client.ObjectKeyFromObject(obj) {
+			requests = append(requests, r.findWorkloadsForModel(ctx, &model)...)
+		}
+	}
+
+	for _, model := range clusterModels.Items {
+		if StoreKey(model.Namespace, model.Spec.StoreRef) == client.ObjectKeyFromObject(obj) {
+			requests = append(requests, r.findWorkloadsForModel(ctx, &model)...)
+		}
+	}
+
+	return requests
+}
@@ -1,12 +1,79 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
 // Config ...
 type Config struct {
 	OpenFGAURL string `envconfig:"OPENFGA_URL" default:"http://host.docker.internal:8080"`
+
+	// APIToken is the pre-shared key to authenticate with the OpenFGA API.
+	APIToken string `envconfig:"OPENFGA_API_TOKEN"`
+	// APITokenFile is the file containing the pre-shared key (e.g. a mounted secret).
+	// The file is read again on changes, so that the key can be rotated.
+	APITokenFile string `envconfig:"OPENFGA_API_TOKEN_FILE"`
+
+	// ClientID is the OAuth2 client ID to authenticate with the OpenFGA API.
+	ClientID string `envconfig:"OPENFGA_CLIENT_ID"`
+	// ClientSecret is the OAuth2 client secret.
+	ClientSecret string `envconfig:"OPENFGA_CLIENT_SECRET"`
+	// ClientSecretFile is the file containing the OAuth2 client secret (e.g. a mounted secret).
+	// The file is read again on changes, so that the secret can be rotated.
+	ClientSecretFile string `envconfig:"OPENFGA_CLIENT_SECRET_FILE"`
+	// APITokenIssuer is the issuer of the OAuth2 tokens.
+	APITokenIssuer string `envconfig:"OPENFGA_API_TOKEN_ISSUER"`
+	// APIAudience is the audience of the OAuth2 tokens.
+	APIAudience string `envconfig:"OPENFGA_API_AUDIENCE"`
+	// APIScopes are the space separated scopes of the OAuth2 tokens.
+	APIScopes string `envconfig:"OPENFGA_API_SCOPES"`
+
+	// TLSCAFile is the file containing the PEM encoded CA bundle to verify the server certificate.
+	TLSCAFile string `envconfig:"OPENFGA_TLS_CA_FILE"`
+	// TLSCertFile is the file containing the PEM encoded client certificate for mutual TLS.
+	TLSCertFile string `envconfig:"OPENFGA_TLS_CERT_FILE"`
+	// TLSKeyFile is the file containing the PEM encoded client key for mutual TLS.
+	TLSKeyFile string `envconfig:"OPENFGA_TLS_KEY_FILE"`
+	// TLSServerName is the name to verify the server certificate against.
+	TLSServerName string `envconfig:"OPENFGA_TLS_SERVER_NAME"`
+	// TLSInsecureSkipVerify disables the verification of the server certificate.
+	TLSInsecureSkipVerify bool `envconfig:"OPENFGA_TLS_INSECURE_SKIP_VERIFY" default:"false"`
+
+	// DefaultDeletionPolicy is the deletion policy of stores and models that do not set one (Delete, Retain or Orphan).
+	DefaultDeletionPolicy string `envconfig:"OPENFGA_DEFAULT_DELETION_POLICY" default:"Delete"`
+	// DefaultDriftPolicy is the drift policy of stores and models that do not set one (Recreate or Flag).
+	DefaultDriftPolicy string `envconfig:"OPENFGA_DEFAULT_DRIFT_POLICY" default:"Recreate"`
+	// ResyncInterval is the interval in which resources are compared with OpenFGA, 0 disables the resync.
+	ResyncInterval time.Duration `envconfig:"OPENFGA_RESYNC_INTERVAL" default:"10m"`
+}
+
+// Credentials are the credentials to authenticate with the OpenFGA API.
+type Credentials struct {
+	APIToken       string
+	ClientID       string
+	ClientSecret   string
+	APITokenIssuer string
+	APIAudience    string
+	APIScopes      string
+}
+
+// TLS is the TLS configuration to connect to the OpenFGA API.
+type TLS struct {
+	CA                 []byte
+	Cert               []byte
+	Key                []byte
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+// Enabled returns true if any TLS setting is configured.
+func (t TLS) Enabled() bool {
+	return len(t.CA) > 0 || len(t.Cert) > 0 || len(t.Key) > 0 || t.ServerName != "" || t.InsecureSkipVerify
 }
 
 // New ...
@@ -21,5 +88,93 @@ func (c *Config) Marshal() error {
 		return err
 	}
 
+	switch c.DefaultDeletionPolicy {
+	case "Delete", "Retain", "Orphan":
+	default:
+		return fmt.Errorf("invalid default deletion policy: %s", c.DefaultDeletionPolicy)
+	}
+
+	switch c.DefaultDriftPolicy {
+	case "Recreate", "Flag":
+	default:
+		return fmt.Errorf("invalid default drift policy: %s", c.DefaultDriftPolicy)
+	}
+
+	if c.ResyncInterval < 0 {
+		return fmt.Errorf("invalid resync interval: %s", c.ResyncInterval)
+	}
+
 	return nil
 }
+
+// Credentials returns the current credentials.
+// The secret files are read on every call to pick up rotated secrets.
+func (c *Config) Credentials() (Credentials, error) {
+	creds := Credentials{
+		APIToken:       c.APIToken,
+		ClientID:       c.ClientID,
+		ClientSecret:   c.ClientSecret,
+		APITokenIssuer: c.APITokenIssuer,
+		APIAudience:    c.APIAudience,
+		APIScopes:      c.APIScopes,
+	}
+
+	if c.APITokenFile != "" {
+		token, err := readSecretFile(c.APITokenFile)
+		if err != nil {
+			return creds, err
+		}
+		creds.APIToken = token
+	}
+
+	if c.ClientSecretFile != "" {
+		secret, err := readSecretFile(c.ClientSecretFile)
+		if err != nil {
+			return creds, err
+		}
+		creds.ClientSecret = secret
+	}
+
+	return creds, nil
+}
+
+// TLS returns the current TLS configuration.
+// The certificate files are read on every call to pick up renewed certificates.
+func (c *Config) TLS() (TLS, error) {
+	t := TLS{
+		ServerName:         c.TLSServerName,
+		InsecureSkipVerify: c.TLSInsecureSkipVerify,
+	}
+
+	files := []struct {
+		name    string
+		content *[]byte
+	}{
+		{c.TLSCAFile, &t.CA},
+		{c.TLSCertFile, &t.Cert},
+		{c.TLSKeyFile, &t.Key},
+	}
+
+	for _, f := range files {
+		if f.name == "" {
+			continue
+		}
+
+		content, err := os.ReadFile(f.name)
+		if err != nil {
+			return t, err
+		}
+		*f.content = content
+	}
+
+	return t, nil
+}
+
+func readSecretFile(name string) (string, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
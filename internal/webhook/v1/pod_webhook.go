@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/zeiss/openfga-operator/controllers"
+	"github.com/zeiss/pkg/utilx"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// podOwnerDepth is the number of owners that are looked up for the model reference of a pod
+// (e.g. pod, replica set and deployment or pod, job and cron job).
+const podOwnerDepth = 3
+
+var podlog = logf.Log.WithName("pod-webhook")
+
+//+kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod-v1.openfga.zeiss.com,admissionReviewVersions=v1
+
+//+kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get
+
+// SetupPodWebhookWithManager registers the webhook for pods in the manager.
+// The URL is the URL of the OpenFGA API of stores that do not reference a server.
+func SetupPodWebhookWithManager(mgr ctrl.Manager, url string) error {
+	return ctrl.NewWebhookManagedBy(mgr, &corev1.Pod{}).
+		WithDefaulter(&PodDefaulter{Client: mgr.GetClient(), Reader: mgr.GetAPIReader(), URL: url}).
+		Complete()
+}
+
+// PodDefaulter injects the model and its store into pods at creation.
+// The model is referenced by the model.ref annotation on the pod or one of its owners.
+// Only pods in namespaces labeled with controllers.InjectionLabel are sent to the webhook.
+type PodDefaulter struct {
+	// Client reads the models, stores, servers and store grants from the cache of the manager.
+	Client client.Reader
+	// Reader reads the owners of the pods from the API server, as they are not cached.
+	Reader client.Reader
+	// URL is the URL of the OpenFGA API of stores that do not reference a server.
+	URL string
+}
+
+var _ admission.Defaulter[*corev1.Pod] = &PodDefaulter{}
+
+// Default ...
+func (d *PodDefaulter) Default(ctx context.Context, pod *corev1.Pod) error {
+	namespace := pod.Namespace
+	if utilx.Empty(namespace) {
+		req, err := admission.RequestFromContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		namespace = req.Namespace
+	}
+
+	// pods are never rejected, as the injection is not required to run them
+	annotations, err := d.annotations(ctx, namespace, pod)
+	if err != nil {
+		podlog.Error(err, "failed to inject model", "name", pod.Name, "generateName", pod.GenerateName, "namespace", namespace)
+		return nil
+	}
+
+	if annotations == nil {
+		return nil
+	}
+
+	env, err := d.env(ctx, namespace, annotations)
+	if err != nil {
+		podlog.Error(err, "failed to inject model", "name", pod.Name, "generateName", pod.GenerateName, "namespace", namespace)
+		return nil
+	}
+
+	if len(env) == 0 {
+		return nil
+	}
+
+	controllers.InjectEnv(&pod.Spec, env, annotations)
+
+	return nil
+}
+
+// env returns the environment of the model referenced by the annotations, it is empty if the model is not yet written.
+func (d *PodDefaulter) env(ctx context.Context, namespace string, annotations map[string]string) ([]corev1.EnvVar, error) {
+	model, err := controllers.FetchModel(ctx, d.Client, namespace, annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := controllers.FetchStore(ctx, d.Client, model.GetNamespace(), model.GetSpec().StoreRef)
+	if err != nil {
+		return nil, err
+	}
+
+	// the environment is set once the model is written to the store
+	if utilx.Empty(model.GetStatus().InstanceID) || utilx.Empty(store.GetStatus().StoreID) {
+		return nil, nil
+	}
+
+	return controllers.ModelEnv(ctx, d.Client, d.URL, namespace, store, model, annotations)
+}
+
+// annotations returns the annotations of the pod or the first of its controlling owners that references a model.
+// It returns nil if none of them references a model.
+func (d *PodDefaulter) annotations(ctx context.Context, namespace string, obj client.Object) (map[string]string, error) {
+	for range podOwnerDepth {
+		if _, ok := obj.GetAnnotations()[controllers.ModelRefAnnotation]; ok {
+			return obj.GetAnnotations(), nil
+		}
+
+		owner := metav1.GetControllerOf(obj)
+		if owner == nil {
+			return nil, nil
+		}
+
+		gv, err := schema.ParseGroupVersion(owner.APIVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gv.WithKind(owner.Kind))
+
+		err = d.Reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: owner.Name}, u)
+		if err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+
+		obj = u
+	}
+
+	if _, ok := obj.GetAnnotations()[controllers.ModelRefAnnotation]; ok {
+		return obj.GetAnnotations(), nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,96 @@
+package v1alpha1
+
+import (
+	"context"
+
+	openfgav1alpha1 "github.com/zeiss/openfga-operator/api/v1alpha1"
+	fga "github.com/zeiss/openfga-operator/pkg/client"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-openfga-zeiss-com-v1alpha1-model,mutating=false,failurePolicy=fail,sideEffects=None,groups=openfga.zeiss.com,resources=models,verbs=create;update,versions=v1alpha1,name=vmodel-v1alpha1.openfga.zeiss.com,admissionReviewVersions=v1
+
+// SetupModelWebhookWithManager registers the webhook for models in the manager.
+func SetupModelWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &openfgav1alpha1.Model{}).
+		WithValidator(&ModelValidator{}).
+		Complete()
+}
+
+// ModelValidator validates models at admission.
+type ModelValidator struct{}
+
+var _ admission.Validator[*openfgav1alpha1.Model] = &ModelValidator{}
+
+// ValidateCreate ...
+func (v *ModelValidator) ValidateCreate(ctx context.Context, model *openfgav1alpha1.Model) (admission.Warnings, error) {
+	return nil, validateModel("Model", model)
+}
+
+// ValidateUpdate ...
+func (v *ModelValidator) ValidateUpdate(ctx context.Context, old, model *openfgav1alpha1.Model) (admission.Warnings, error) {
+	return nil, validateModel("Model", model)
+}
+
+// ValidateDelete ...
+func (v *ModelValidator) ValidateDelete(ctx context.Context, model *openfgav1alpha1.Model) (admission.Warnings, error) {
+	return nil, nil
+}
+
+//+kubebuilder:webhook:path=/validate-openfga-zeiss-com-v1alpha1-clustermodel,mutating=false,failurePolicy=fail,sideEffects=None,groups=openfga.zeiss.com,resources=clustermodels,verbs=create;update,versions=v1alpha1,name=vclustermodel-v1alpha1.openfga.zeiss.com,admissionReviewVersions=v1
+
+// SetupClusterModelWebhookWithManager registers the webhook for cluster models in the manager.
+func SetupClusterModelWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &openfgav1alpha1.ClusterModel{}).
+		WithValidator(&ClusterModelValidator{}).
+		Complete()
+}
+
+// ClusterModelValidator validates cluster models at admission.
+type ClusterModelValidator struct{}
+
+var _ admission.Validator[*openfgav1alpha1.ClusterModel] = &ClusterModelValidator{}
+
+// ValidateCreate ...
+func (v *ClusterModelValidator) ValidateCreate(ctx context.Context, model *openfgav1alpha1.ClusterModel) (admission.Warnings, error) {
+	return nil, validateModel("ClusterModel", model)
+}
+
+// ValidateUpdate ...
+func (v *ClusterModelValidator) ValidateUpdate(ctx context.Context, old, model *openfgav1alpha1.ClusterModel) (admission.Warnings, error) {
+	return nil, validateModel("ClusterModel", model)
+}
+
+// ValidateDelete ...
+func (v *ClusterModelValidator) ValidateDelete(ctx context.Context, model *openfgav1alpha1.ClusterModel) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateModel(kind string, model openfgav1alpha1.GenericModel) error {
+	errs := field.ErrorList{}
+	spec := field.NewPath("spec")
+	m := model.GetSpec()
+
+	if m.StoreRef.Name == "" {
+		errs = append(errs, field.Required(spec.Child("storeRef", "name"), "the store of the model must be set"))
+	}
+
+	// models loaded from a source are validated by the controller
+	if m.Model != "" {
+		if err := fga.CheckFormat(m.Model, fga.ModelFormat(m.Format)); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("format"), m.Format, err.Error()))
+		} else if err := fga.ValidateModel(m.Model); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("model"), field.OmitValueType{}, err.Error()))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errors.NewInvalid(openfgav1alpha1.GroupVersion.WithKind(kind).GroupKind(), model.GetName(), errs)
+}
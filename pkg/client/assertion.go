@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+
+	openfga "github.com/openfga/go-sdk/client"
+	"github.com/zeiss/pkg/cast"
+)
+
+// Assertion is the expected result of a check against an authorization model.
+type Assertion struct {
+	Tuple
+	// Expectation is the expected result of the check.
+	Expectation bool `json:"expectation"`
+	// Context is the context of the check.
+	Context map[string]interface{} `json:"context,omitempty"`
+	// ContextualTuples are the tuples that are considered in addition to the tuples in the store.
+	ContextualTuples []Tuple `json:"contextualTuples,omitempty"`
+}
+
+// Check returns true if the user has the relation to the object in the authorization model.
+func (c *Client) Check(ctx context.Context, store, model string, tuple Tuple, checkContext map[string]interface{}, contextual []Tuple) (bool, error) {
+	body := openfga.ClientCheckRequest{
+		User:             tuple.User,
+		Relation:         tuple.Relation,
+		Object:           tuple.Object,
+		ContextualTuples: contextualTuples(contextual),
+	}
+
+	if checkContext != nil {
+		body.Context = cast.Ptr(checkContext)
+	}
+
+	opts := openfga.ClientCheckOptions{
+		StoreId:              cast.Ptr(store),
+		AuthorizationModelId: cast.Ptr(model),
+	}
+
+	resp, err := c.fga.Check(ctx).Options(opts).Body(body).Execute()
+	if err != nil {
+		return false, err
+	}
+
+	return resp.GetAllowed(), nil
+}
+
+// ListObjects returns the objects of the type the user has the relation to in the authorization model.
+func (c *Client) ListObjects(ctx context.Context, store, model, user, relation, typ string, checkContext map[string]interface{}, contextual []Tuple) ([]string, error) {
+	body := openfga.ClientListObjectsRequest{
+		User:             user,
+		Relation:         relation,
+		Type:             typ,
+		ContextualTuples: contextualTuples(contextual),
+	}
+
+	if checkContext != nil {
+		body.Context = cast.Ptr(checkContext)
+	}
+
+	opts := openfga.ClientListObjectsOptions{
+		StoreId:              cast.Ptr(store),
+		AuthorizationModelId: cast.Ptr(model),
+	}
+
+	resp, err := c.fga.ListObjects(ctx).Options(opts).Body(body).Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.GetObjects(), nil
+}
+
+// WriteAssertions replaces the assertions of the authorization model.
+func (c *Client) WriteAssertions(ctx context.Context, store, model string, assertions []Assertion) error {
+	body := openfga.ClientWriteAssertionsRequest{}
+	for _, a := range assertions {
+		assertion := openfga.ClientAssertion{
+			User:             a.User,
+			Relation:         a.Relation,
+			Object:           a.Object,
+			Expectation:      a.Expectation,
+			ContextualTuples: contextualTuples(a.ContextualTuples),
+		}
+
+		if a.Context != nil {
+			assertion.Context = cast.Ptr(a.Context)
+		}
+
+		body = append(body, assertion)
+	}
+
+	opts := openfga.ClientWriteAssertionsOptions{
+		StoreId:              cast.Ptr(store),
+		AuthorizationModelId: cast.Ptr(model),
+	}
+
+	_, err := c.fga.WriteAssertions(ctx).Options(opts).Body(body).Execute()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func contextualTuples(tuples []Tuple) []openfga.ClientContextualTupleKey {
+	keys := make([]openfga.ClientContextualTupleKey, 0, len(tuples))
+	for _, t := range tuples {
+		keys = append(keys, t.tupleKey())
+	}
+
+	return keys
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"sync"
+)
+
+// Cache caches clients by key and version.
+type Cache struct {
+	clients map[string]cachedClient
+	mu      sync.Mutex
+}
+
+type cachedClient struct {
+	version string
+	client  *Client
+}
+
+// NewCache ...
+func NewCache() *Cache {
+	return &Cache{
+		clients: make(map[string]cachedClient),
+	}
+}
+
+// Get returns the cached client for the key.
+// A new client is created if there is no client or the version has changed.
+func (c *Cache) Get(key, version string, fn func() (*Client, error)) (*Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.clients[key]; ok && cached.version == version {
+		return cached.client, nil
+	}
+
+	client, err := fn()
+	if err != nil {
+		return nil, err
+	}
+
+	c.clients[key] = cachedClient{
+		version: version,
+		client:  client,
+	}
+
+	return client, nil
+}
+
+// Delete removes the client for the key.
+func (c *Cache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.clients, key)
+}
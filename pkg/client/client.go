@@ -1,7 +1,14 @@
 package client
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+
+	sdk "github.com/openfga/go-sdk"
 	openfga "github.com/openfga/go-sdk/client"
+	"github.com/openfga/go-sdk/credentials"
 )
 
 const LocalApiURL = "http://host.docker.internal:8080"
@@ -11,12 +18,118 @@ type Client struct {
 	fga *openfga.OpenFgaClient
 }
 
+// Opt is a client option.
+type Opt func(*Opts)
+
+// Opts are the options for the client.
+type Opts struct {
+	// APIToken is the pre-shared key to authenticate with the API.
+	APIToken string
+	// ClientCredentials are the OAuth2 client credentials to authenticate with the API.
+	ClientCredentials *ClientCredentials
+	// TLS is the TLS configuration to connect to the API.
+	TLS *TLS
+}
+
+// TLS ...
+type TLS struct {
+	// CA is the PEM encoded CA bundle to verify the server certificate.
+	// It is added to the system certificate pool.
+	CA []byte
+	// Cert is the PEM encoded client certificate for mutual TLS.
+	Cert []byte
+	// Key is the PEM encoded client key for mutual TLS.
+	Key []byte
+	// ServerName is the name to verify the server certificate against.
+	ServerName string
+	// InsecureSkipVerify disables the verification of the server certificate.
+	InsecureSkipVerify bool
+}
+
+// ClientCredentials ...
+type ClientCredentials struct {
+	// ClientID ...
+	ClientID string
+	// ClientSecret ...
+	ClientSecret string
+	// TokenIssuer ...
+	TokenIssuer string
+	// Audience ...
+	Audience string
+	// Scopes ...
+	Scopes string
+}
+
+// Configure is configuring the client.
+func (o *Opts) Configure(opts ...Opt) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// WithAPIToken is setting the pre-shared key.
+func WithAPIToken(token string) Opt {
+	return func(o *Opts) {
+		o.APIToken = token
+	}
+}
+
+// WithClientCredentials is setting the OAuth2 client credentials.
+func WithClientCredentials(creds ClientCredentials) Opt {
+	return func(o *Opts) {
+		o.ClientCredentials = &creds
+	}
+}
+
+// WithTLS is setting the TLS configuration.
+func WithTLS(t TLS) Opt {
+	return func(o *Opts) {
+		o.TLS = &t
+	}
+}
+
 // NewClient ...
-func NewClient(apiURL string) (*Client, error) {
+func NewClient(apiURL string, opts ...Opt) (*Client, error) {
+	options := new(Opts)
+	options.Configure(opts...)
+
 	cfg := &openfga.ClientConfiguration{
 		ApiUrl: apiURL,
 	}
 
+	if options.APIToken != "" {
+		cfg.Credentials = &credentials.Credentials{
+			Method: credentials.CredentialsMethodApiToken,
+			Config: &credentials.Config{
+				ApiToken: options.APIToken,
+			},
+		}
+	}
+
+	if options.ClientCredentials != nil {
+		cfg.Credentials = &credentials.Credentials{
+			Method: credentials.CredentialsMethodClientCredentials,
+			Config: &credentials.Config{
+				ClientCredentialsClientId:       options.ClientCredentials.ClientID,
+				ClientCredentialsClientSecret:   options.ClientCredentials.ClientSecret,
+				ClientCredentialsApiTokenIssuer: options.ClientCredentials.TokenIssuer,
+				ClientCredentialsApiAudience:    options.ClientCredentials.Audience,
+				ClientCredentialsScopes:         options.ClientCredentials.Scopes,
+			},
+		}
+	}
+
+	if options.TLS != nil {
+		tlsConfig, err := options.TLS.config()
+		if err != nil {
+			return nil, err
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		cfg.HTTPClient = &http.Client{Transport: transport}
+	}
+
 	fga, err := openfga.NewSdkClient(cfg)
 	if err != nil {
 		return nil, err
@@ -26,3 +139,56 @@ func NewClient(apiURL string) (*Client, error) {
 		fga: fga,
 	}, nil
 }
+
+func (t *TLS) config() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify, // #nosec G402
+	}
+
+	if len(t.CA) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(t.CA) {
+			return nil, errors.New("failed to parse CA certificates")
+		}
+
+		cfg.RootCAs = pool
+	}
+
+	if len(t.Cert) > 0 || len(t.Key) > 0 {
+		cert, err := tls.X509KeyPair(t.Cert, t.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// IsNotFound returns true if the error is a not found error of the OpenFGA API.
+func IsNotFound(err error) bool {
+	var notFound sdk.FgaApiNotFoundError
+	return errors.As(err, &notFound)
+}
+
+// IsModelNotFound returns true if the error reports an authorization model that does not exist in the store.
+// OpenFGA reports unknown authorization models as validation errors instead of not found errors.
+func IsModelNotFound(err error) bool {
+	if IsNotFound(err) {
+		return true
+	}
+
+	var validation sdk.FgaApiValidationError
+	if !errors.As(err, &validation) {
+		return false
+	}
+
+	return validation.ResponseCode() == sdk.ERRORCODE_AUTHORIZATION_MODEL_NOT_FOUND
+}
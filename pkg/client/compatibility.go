@@ -0,0 +1,96 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+)
+
+// IncompatibleTuple is a tuple that can no longer be written with a model.
+type IncompatibleTuple struct {
+	Tuple
+	// Reason is the reason the tuple is incompatible.
+	Reason string `json:"reason"`
+}
+
+// IncompatibleTuples returns the tuples that use types, relations or user types
+// which are not defined in the model (e.g. after a relation was renamed or removed).
+func IncompatibleTuples(spec string, tuples []Tuple) ([]IncompatibleTuple, error) {
+	model, err := parseModel(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	types := map[string]*openfgav1.TypeDefinition{}
+	for _, td := range model.GetTypeDefinitions() {
+		types[td.GetType()] = td
+	}
+
+	incompatible := []IncompatibleTuple{}
+	for _, t := range tuples {
+		reason := incompatibility(types, t)
+		if reason == "" {
+			continue
+		}
+
+		incompatible = append(incompatible, IncompatibleTuple{Tuple: t, Reason: reason})
+	}
+
+	return incompatible, nil
+}
+
+func incompatibility(types map[string]*openfgav1.TypeDefinition, t Tuple) string {
+	objectType, _, _ := strings.Cut(t.Object, ":")
+
+	td, ok := types[objectType]
+	if !ok {
+		return fmt.Sprintf("type %s is not defined", objectType)
+	}
+
+	if _, ok := td.GetRelations()[t.Relation]; !ok {
+		return fmt.Sprintf("relation %s is not defined on type %s", t.Relation, objectType)
+	}
+
+	refs := td.GetMetadata().GetRelations()[t.Relation].GetDirectlyRelatedUserTypes()
+	if len(refs) == 0 {
+		return fmt.Sprintf("relation %s of type %s is not directly assignable", t.Relation, objectType)
+	}
+
+	userType, id, _ := strings.Cut(t.User, ":")
+	_, userRelation, _ := strings.Cut(id, "#")
+
+	condition := ""
+	if t.Condition != nil {
+		condition = t.Condition.Name
+	}
+
+	for _, ref := range refs {
+		if ref.GetType() != userType || ref.GetCondition() != condition {
+			continue
+		}
+
+		switch {
+		case userRelation != "" && ref.GetRelation() == userRelation:
+			return ""
+		case userRelation == "" && id == "*" && ref.GetWildcard() != nil:
+			return ""
+		case userRelation == "" && id != "*" && ref.GetRelation() == "" && ref.GetWildcard() == nil:
+			return ""
+		}
+	}
+
+	user := userType
+	switch {
+	case userRelation != "":
+		user += "#" + userRelation
+	case id == "*":
+		user += ":*"
+	}
+
+	if condition != "" {
+		user += " with " + condition
+	}
+
+	return fmt.Sprintf("user type %s is not allowed in relation %s of type %s", user, t.Relation, objectType)
+}
@@ -0,0 +1,142 @@
+package client
+
+import "testing"
+
+func TestIncompatibleTuples(t *testing.T) {
+	spec := `model
+  schema 1.1
+
+type user
+
+type team
+  relations
+    define member: [user]
+
+type document
+  relations
+    define owner: [user]
+    define editor: [user, team#member]
+    define viewer: [user, user:*, user with non_expired]
+    define reader: viewer
+
+condition non_expired(current_time: timestamp, expires: timestamp) {
+  current_time < expires
+}`
+
+	tests := []struct {
+		name   string
+		tuple  Tuple
+		reason string
+	}{
+		{
+			name:  "compatible tuple",
+			tuple: Tuple{User: "user:anne", Relation: "owner", Object: "document:roadmap"},
+		},
+		{
+			name:   "removed type",
+			tuple:  Tuple{User: "user:anne", Relation: "owner", Object: "folder:plans"},
+			reason: "type folder is not defined",
+		},
+		{
+			name:   "removed relation",
+			tuple:  Tuple{User: "user:anne", Relation: "commenter", Object: "document:roadmap"},
+			reason: "relation commenter is not defined on type document",
+		},
+		{
+			name:   "relation not directly assignable",
+			tuple:  Tuple{User: "user:anne", Relation: "reader", Object: "document:roadmap"},
+			reason: "relation reader of type document is not directly assignable",
+		},
+		{
+			name:   "user type no longer allowed",
+			tuple:  Tuple{User: "team:core", Relation: "owner", Object: "document:roadmap"},
+			reason: "user type team is not allowed in relation owner of type document",
+		},
+		{
+			name:  "allowed wildcard",
+			tuple: Tuple{User: "user:*", Relation: "viewer", Object: "document:roadmap"},
+		},
+		{
+			name:   "wildcard no longer allowed",
+			tuple:  Tuple{User: "user:*", Relation: "owner", Object: "document:roadmap"},
+			reason: "user type user:* is not allowed in relation owner of type document",
+		},
+		{
+			name:  "allowed userset",
+			tuple: Tuple{User: "team:core#member", Relation: "editor", Object: "document:roadmap"},
+		},
+		{
+			name:   "userset no longer allowed",
+			tuple:  Tuple{User: "team:core#member", Relation: "viewer", Object: "document:roadmap"},
+			reason: "user type team#member is not allowed in relation viewer of type document",
+		},
+		{
+			name:  "allowed conditional tuple",
+			tuple: Tuple{User: "user:anne", Relation: "viewer", Object: "document:roadmap", Condition: &Condition{Name: "non_expired"}},
+		},
+		{
+			name:   "condition no longer allowed",
+			tuple:  Tuple{User: "user:anne", Relation: "owner", Object: "document:roadmap", Condition: &Condition{Name: "non_expired"}},
+			reason: "user type user with non_expired is not allowed in relation owner of type document",
+		},
+		{
+			name:   "removed condition",
+			tuple:  Tuple{User: "user:anne", Relation: "viewer", Object: "document:roadmap", Condition: &Condition{Name: "in_region"}},
+			reason: "user type user with in_region is not allowed in relation viewer of type document",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			incompatible, err := IncompatibleTuples(spec, []Tuple{tc.tuple})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.reason == "" {
+				if len(incompatible) != 0 {
+					t.Errorf("expected a compatible tuple, got %q", incompatible[0].Reason)
+				}
+
+				return
+			}
+
+			if len(incompatible) != 1 {
+				t.Fatalf("expected an incompatible tuple, got %d", len(incompatible))
+			}
+
+			if incompatible[0].Reason != tc.reason {
+				t.Errorf("expected the reason %q, got %q", tc.reason, incompatible[0].Reason)
+			}
+		})
+	}
+}
+
+func TestIncompatibleTuplesCompatibleChange(t *testing.T) {
+	// the model adds a type and a relation and allows more user types
+	spec := `model
+  schema 1.1
+
+type user
+
+type folder
+
+type document
+  relations
+    define owner: [user, user:*]
+    define viewer: [user] or owner`
+
+	tuples := []Tuple{
+		{User: "user:anne", Relation: "owner", Object: "document:roadmap"},
+		{User: "user:bob", Relation: "viewer", Object: "document:roadmap"},
+	}
+
+	incompatible, err := IncompatibleTuples(spec, tuples)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(incompatible) != 0 {
+		t.Errorf("expected no incompatible tuples, got %v", incompatible)
+	}
+}
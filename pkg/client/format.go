@@ -0,0 +1,52 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	"github.com/openfga/language/pkg/go/transformer"
+)
+
+// ModelFormat is the format of an authorization model.
+type ModelFormat string
+
+const (
+	// FormatDSL is the OpenFGA modeling language.
+	FormatDSL ModelFormat = "dsl"
+	// FormatJSON is the JSON of an authorization model as written by the OpenFGA API.
+	FormatJSON ModelFormat = "json"
+)
+
+// DetectFormat returns the format of the model.
+// Models in JSON are objects, all other models are in DSL.
+func DetectFormat(spec string) ModelFormat {
+	if strings.HasPrefix(strings.TrimSpace(spec), "{") {
+		return FormatJSON
+	}
+
+	return FormatDSL
+}
+
+// CheckFormat returns an error if the model is not in the format.
+// An empty format matches all models.
+func CheckFormat(spec string, format ModelFormat) error {
+	if format == "" {
+		return nil
+	}
+
+	if detected := DetectFormat(spec); detected != format {
+		return fmt.Errorf("model is in %s format, expected %s", detected, format)
+	}
+
+	return nil
+}
+
+// parseModel parses the model in DSL or JSON.
+func parseModel(spec string) (*openfgav1.AuthorizationModel, error) {
+	if DetectFormat(spec) == FormatJSON {
+		return transformer.LoadJSONStringToProto(spec)
+	}
+
+	return transformer.TransformDSLToProto(spec)
+}
@@ -3,10 +3,14 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"slices"
+	"strings"
 
+	sdk "github.com/openfga/go-sdk"
 	openfga "github.com/openfga/go-sdk/client"
 	"github.com/openfga/language/pkg/go/transformer"
 	"github.com/zeiss/pkg/cast"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 // AuthorizationModel ...
@@ -17,16 +21,21 @@ type AuthorizationModel struct {
 
 // CreateModel ...
 func (c *Client) CreateModel(ctx context.Context, id, spec string) (*AuthorizationModel, error) {
-	s, err := transformer.TransformDSLToJSON(spec)
+	return c.UpdateModel(ctx, id, spec)
+}
+
+// UpdateModel writes the model in DSL or JSON as a new authorization model.
+func (c *Client) UpdateModel(ctx context.Context, id, spec string) (*AuthorizationModel, error) {
+	body, err := TransformModel(spec)
 	if err != nil {
 		return nil, err
 	}
 
-	var body openfga.ClientWriteAuthorizationModelRequest
-	if err := json.Unmarshal([]byte(s), &body); err != nil {
-		return nil, err
-	}
+	return c.WriteModel(ctx, id, cast.Value(body))
+}
 
+// WriteModel writes the structured model as a new authorization model.
+func (c *Client) WriteModel(ctx context.Context, id string, body openfga.ClientWriteAuthorizationModelRequest) (*AuthorizationModel, error) {
 	resp, err := c.fga.WriteAuthorizationModel(ctx).Options(openfga.ClientWriteAuthorizationModelOptions{StoreId: cast.Ptr(id)}).Body(body).Execute()
 	if err != nil {
 		return nil, err
@@ -39,63 +48,179 @@ func (c *Client) CreateModel(ctx context.Context, id, spec string) (*Authorizati
 	return cast.Ptr(model), nil
 }
 
-// UpdateModel ...
-func (c *Client) UpdateModel(ctx context.Context, id, spec string) (*AuthorizationModel, error) {
-	s, err := transformer.TransformDSLToJSON(spec)
+// GetAuthorizationModel ...
+func (c *Client) GetAuthorizationModel(ctx context.Context, store, model string) (*AuthorizationModel, error) {
+	resp, err := c.fga.ReadAuthorizationModel(ctx).Options(openfga.ClientReadAuthorizationModelOptions{StoreId: cast.Ptr(store), AuthorizationModelId: cast.Ptr(model)}).Execute()
 	if err != nil {
 		return nil, err
 	}
 
-	var body openfga.ClientWriteAuthorizationModelRequest
-	if err := json.Unmarshal([]byte(s), &body); err != nil {
+	j, err := resp.GetAuthorizationModel().MarshalJSON()
+	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.fga.WriteAuthorizationModel(ctx).Options(openfga.ClientWriteAuthorizationModelOptions{StoreId: cast.Ptr(id)}).Body(body).Execute()
+	authModel := AuthorizationModel{
+		ID:   resp.AuthorizationModel.GetId(),
+		Spec: string(j),
+	}
+
+	// modular models can not be transformed to DSL and keep their JSON
+	m, err := transformer.TransformJSONStringToDSL(string(j))
+	if err == nil {
+		authModel.Spec = cast.Value(m)
+	}
+
+	return cast.Ptr(authModel), nil
+}
+
+// NeedsUpdate returns true if the model in the store differs semantically from the update.
+// A model that does not exist in the store always needs an update.
+func (c *Client) NeedsUpdate(ctx context.Context, store, model, update string) (bool, error) {
+	current, err := c.ReadModel(ctx, store, model)
+	if IsModelNotFound(err) {
+		return true, nil
+	}
+
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 
-	model := AuthorizationModel{
-		ID: resp.AuthorizationModelId,
+	return differs(cast.Value(current), update)
+}
+
+// differs returns true if the model in DSL or JSON differs semantically from the current model.
+func differs(current openfga.ClientWriteAuthorizationModelRequest, update string) (bool, error) {
+	body, err := TransformModel(update)
+	if err != nil {
+		return false, err
 	}
 
-	return cast.Ptr(model), nil
+	currentJSON, err := CanonicalModelJSON(current)
+	if err != nil {
+		return false, err
+	}
+
+	updateJSON, err := CanonicalModelJSON(cast.Value(body))
+	if err != nil {
+		return false, err
+	}
+
+	return currentJSON != updateJSON, nil
 }
 
-// GetAuthorizationModel ...
-func (c *Client) GetAuthorizationModel(ctx context.Context, store, model string) (*AuthorizationModel, error) {
+// ReadModel returns the authorization model of the store in the structure it is written with,
+// e.g. to write it to another store.
+func (c *Client) ReadModel(ctx context.Context, store, model string) (*openfga.ClientWriteAuthorizationModelRequest, error) {
 	resp, err := c.fga.ReadAuthorizationModel(ctx).Options(openfga.ClientReadAuthorizationModelOptions{StoreId: cast.Ptr(store), AuthorizationModelId: cast.Ptr(model)}).Execute()
 	if err != nil {
 		return nil, err
 	}
 
-	j, err := resp.GetAuthorizationModel().MarshalJSON()
+	current := resp.GetAuthorizationModel()
+
+	return &openfga.ClientWriteAuthorizationModelRequest{
+		SchemaVersion:   current.GetSchemaVersion(),
+		TypeDefinitions: current.GetTypeDefinitions(),
+		Conditions:      current.Conditions,
+	}, nil
+}
+
+// CopyModel writes the authorization model of the store as a new authorization model to another store.
+func (c *Client) CopyModel(ctx context.Context, store, model, to string) (*AuthorizationModel, error) {
+	body, err := c.ReadModel(ctx, store, model)
 	if err != nil {
 		return nil, err
 	}
 
-	m, err := transformer.TransformJSONStringToDSL(string(j))
+	return c.WriteModel(ctx, to, cast.Value(body))
+}
+
+// CanonicalModelJSON returns a canonical JSON form of the model.
+// The type definitions are sorted by type and empty values are removed,
+// so that models which only differ in formatting have the same form.
+func CanonicalModelJSON(model openfga.ClientWriteAuthorizationModelRequest) (string, error) {
+	if model.Conditions != nil && len(*model.Conditions) == 0 {
+		model.Conditions = nil
+	}
+
+	model.TypeDefinitions = slices.Clone(model.TypeDefinitions)
+	slices.SortStableFunc(model.TypeDefinitions, func(a, b sdk.TypeDefinition) int {
+		return strings.Compare(a.GetType(), b.GetType())
+	})
+
+	b, err := json.Marshal(model)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	authModel := AuthorizationModel{
-		ID:   resp.AuthorizationModel.GetId(),
-		Spec: cast.Value(m),
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return "", err
 	}
 
-	return cast.Ptr(authModel), nil
+	// json.Marshal sorts the keys of maps
+	b, err = json.Marshal(compact(v))
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
 }
 
-// NeedsUpdate ...
-func (c *Client) NeedsUpdate(ctx context.Context, store, model, update string) (bool, error) {
-	m, err := c.GetAuthorizationModel(ctx, store, model)
+// compact removes null values, empty strings and empty arrays.
+// Empty objects are kept as they carry meaning (e.g. {"this": {}}).
+func compact(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			if c := compact(v); c != nil {
+				m[k] = c
+			}
+		}
+
+		return m
+	case []interface{}:
+		if len(t) == 0 {
+			return nil
+		}
+
+		l := make([]interface{}, 0, len(t))
+		for _, v := range t {
+			l = append(l, compact(v))
+		}
+
+		return l
+	case string:
+		if t == "" {
+			return nil
+		}
+
+		return t
+	default:
+		return v
+	}
+}
+
+// TransformModel transforms the model in DSL or JSON into its structured form.
+func TransformModel(spec string) (*openfga.ClientWriteAuthorizationModelRequest, error) {
+	model, err := parseModel(spec)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+
+	s, err := protojson.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+
+	var body openfga.ClientWriteAuthorizationModelRequest
+	if err := json.Unmarshal(s, &body); err != nil {
+		return nil, err
 	}
 
-	return m.Spec != update, nil
+	return cast.Ptr(body), nil
 }
 
 // DeleteAuthorizationModel ...
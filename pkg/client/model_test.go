@@ -0,0 +1,155 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/zeiss/pkg/cast"
+)
+
+const testModel = `model
+  schema 1.1
+
+type user
+
+type document
+  relations
+    define owner: [user]
+    define viewer: [user] or owner`
+
+func TestDiffers(t *testing.T) {
+	tests := []struct {
+		name   string
+		update string
+		want   bool
+	}{
+		{
+			name:   "same model",
+			update: testModel,
+			want:   false,
+		},
+		{
+			name: "whitespace and comments",
+			update: `# documents of the users
+model
+  schema 1.1
+
+type user
+
+
+type document
+  relations
+    # the owner of the document
+    define owner: [user]
+    define viewer:   [user]   or   owner
+`,
+			want: false,
+		},
+		{
+			name: "reordered type definitions",
+			update: `model
+  schema 1.1
+
+type document
+  relations
+    define owner: [user]
+    define viewer: [user] or owner
+
+type user`,
+			want: false,
+		},
+		{
+			name:   "json model equal to the dsl model",
+			update: `{"schema_version":"1.1","type_definitions":[{"type":"user"},{"type":"document","relations":{"owner":{"this":{}},"viewer":{"union":{"child":[{"this":{}},{"computedUserset":{"relation":"owner"}}]}}},"metadata":{"relations":{"owner":{"directly_related_user_types":[{"type":"user"}]},"viewer":{"directly_related_user_types":[{"type":"user"}]}}}}]}`,
+			want:   false,
+		},
+		{
+			name: "changed relation",
+			update: `model
+  schema 1.1
+
+type user
+
+type document
+  relations
+    define owner: [user]
+    define viewer: [user] and owner`,
+			want: true,
+		},
+		{
+			name: "changed directly related types",
+			update: `model
+  schema 1.1
+
+type user
+
+type document
+  relations
+    define owner: [user, user:*]
+    define viewer: [user] or owner`,
+			want: true,
+		},
+		{
+			name: "added type",
+			update: testModel + `
+
+type folder`,
+			want: true,
+		},
+	}
+
+	current, err := TransformModel(testModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := differs(cast.Value(current), tc.update)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tc.want {
+				t.Errorf("expected differs=%t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		name string
+		in   interface{}
+		want string
+	}{
+		{
+			name: "empty values",
+			in:   map[string]interface{}{"a": "", "b": nil, "c": []interface{}{}, "d": "x"},
+			want: `{"d":"x"}`,
+		},
+		{
+			name: "empty objects",
+			in:   map[string]interface{}{"this": map[string]interface{}{}},
+			want: `{"this":{}}`,
+		},
+		{
+			name: "nested values",
+			in:   map[string]interface{}{"child": []interface{}{map[string]interface{}{"relation": "owner", "object": ""}}},
+			want: `{"child":[{"relation":"owner"}]}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(compact(tc.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(b) != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/openfga/language/pkg/go/transformer"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// ModFile is the name of the file that defines the modules of a modular model.
+const ModFile = "fga.mod"
+
+// TransformModules combines the modules of a modular model into a single authorization model.
+// The module files are keyed by their path in the fga.mod file. It returns the JSON of the model.
+func TransformModules(mod string, files map[string]string) (string, error) {
+	m, err := transformer.TransformModFile(mod)
+	if err != nil {
+		return "", err
+	}
+
+	modules := []transformer.ModuleFile{}
+	for _, path := range m.Contents.Value {
+		contents, ok := files[path.Value]
+		if !ok {
+			return "", fmt.Errorf("module file %s not found", path.Value)
+		}
+
+		modules = append(modules, transformer.ModuleFile{Name: path.Value, Contents: contents})
+	}
+
+	model, err := transformer.TransformModuleFilesToModel(modules, m.Schema.Value)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := protojson.Marshal(model)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
@@ -45,6 +45,43 @@ func (c *Client) GetStore(ctx context.Context, id string) (*Store, error) {
 	return cast.Ptr(store), nil
 }
 
+// ListStores returns all stores, optionally filtered by name.
+func (c *Client) ListStores(ctx context.Context, name string) ([]Store, error) {
+	stores := []Store{}
+	opts := openfga.ClientListStoresOptions{}
+
+	if name != "" {
+		opts.Name = cast.Ptr(name)
+	}
+
+	for {
+		resp, err := c.fga.ListStores(ctx).Options(opts).Execute()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range resp.GetStores() {
+			// older servers do not support filtering by name
+			if name != "" && s.GetName() != name {
+				continue
+			}
+
+			stores = append(stores, Store{
+				ID:   s.GetId(),
+				Name: s.GetName(),
+			})
+		}
+
+		if resp.GetContinuationToken() == "" {
+			break
+		}
+
+		opts.ContinuationToken = cast.Ptr(resp.GetContinuationToken())
+	}
+
+	return stores, nil
+}
+
 // DeleteStore ...
 func (c *Client) DeleteStore(ctx context.Context, id string) error {
 	_, err := c.fga.DeleteStore(ctx).Options(openfga.ClientDeleteStoreOptions{StoreId: cast.Ptr(id)}).Execute()
@@ -0,0 +1,184 @@
+package client
+
+import (
+	"context"
+	"slices"
+
+	sdk "github.com/openfga/go-sdk"
+	openfga "github.com/openfga/go-sdk/client"
+	"github.com/zeiss/pkg/cast"
+)
+
+// Tuple ...
+type Tuple struct {
+	// User ...
+	User string `json:"user"`
+	// Relation ...
+	Relation string `json:"relation"`
+	// Object ...
+	Object string `json:"object"`
+	// Condition ...
+	Condition *Condition `json:"condition,omitempty"`
+}
+
+// Condition ...
+type Condition struct {
+	// Name ...
+	Name string `json:"name"`
+	// Context ...
+	Context map[string]interface{} `json:"context,omitempty"`
+}
+
+func (t Tuple) tupleKey() openfga.ClientTupleKey {
+	key := openfga.ClientTupleKey{
+		User:     t.User,
+		Relation: t.Relation,
+		Object:   t.Object,
+	}
+
+	if t.Condition != nil {
+		key.Condition = &sdk.RelationshipCondition{
+			Name: t.Condition.Name,
+		}
+
+		if t.Condition.Context != nil {
+			key.Condition.Context = cast.Ptr(t.Condition.Context)
+		}
+	}
+
+	return key
+}
+
+func (t Tuple) tupleKeyWithoutCondition() openfga.ClientTupleKeyWithoutCondition {
+	return openfga.ClientTupleKeyWithoutCondition{
+		User:     t.User,
+		Relation: t.Relation,
+		Object:   t.Object,
+	}
+}
+
+// MaxTuplesPerWrite is the maximum number of tuples OpenFGA accepts in a single write.
+const MaxTuplesPerWrite = 100
+
+// WriteTuple ...
+func (c *Client) WriteTuple(ctx context.Context, store string, tuple Tuple) error {
+	return c.WriteTuples(ctx, store, []Tuple{tuple})
+}
+
+// DeleteTuple ...
+func (c *Client) DeleteTuple(ctx context.Context, store string, tuple Tuple) error {
+	return c.DeleteTuples(ctx, store, []Tuple{tuple})
+}
+
+// WriteTuples writes the tuples in batches of MaxTuplesPerWrite.
+// A batch fails if one of its tuples already exists in the store,
+// so that tuples written by others are never taken for written by the caller.
+func (c *Client) WriteTuples(ctx context.Context, store string, tuples []Tuple) error {
+	opts := openfga.ClientWriteOptions{
+		StoreId: cast.Ptr(store),
+		Conflict: openfga.ClientWriteConflictOptions{
+			OnDuplicateWrites: openfga.CLIENT_WRITE_REQUEST_ON_DUPLICATE_WRITES_ERROR,
+		},
+	}
+
+	for batch := range slices.Chunk(tuples, MaxTuplesPerWrite) {
+		body := openfga.ClientWriteTuplesBody{}
+		for _, t := range batch {
+			body = append(body, t.tupleKey())
+		}
+
+		_, err := c.fga.WriteTuples(ctx).Options(opts).Body(body).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteTuples deletes the tuples in batches of MaxTuplesPerWrite.
+// Tuples that do not exist in the store are ignored.
+func (c *Client) DeleteTuples(ctx context.Context, store string, tuples []Tuple) error {
+	opts := openfga.ClientWriteOptions{
+		StoreId: cast.Ptr(store),
+		Conflict: openfga.ClientWriteConflictOptions{
+			OnMissingDeletes: openfga.CLIENT_WRITE_REQUEST_ON_MISSING_DELETES_IGNORE,
+		},
+	}
+
+	for batch := range slices.Chunk(tuples, MaxTuplesPerWrite) {
+		body := openfga.ClientDeleteTuplesBody{}
+		for _, t := range batch {
+			body = append(body, t.tupleKeyWithoutCondition())
+		}
+
+		_, err := c.fga.DeleteTuples(ctx).Options(opts).Body(body).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HasTuple returns true if the tuple exists in the store, regardless of its condition.
+func (c *Client) HasTuple(ctx context.Context, store string, tuple Tuple) (bool, error) {
+	opts := openfga.ClientReadOptions{
+		StoreId: cast.Ptr(store),
+	}
+
+	body := openfga.ClientReadRequest{
+		User:     cast.Ptr(tuple.User),
+		Relation: cast.Ptr(tuple.Relation),
+		Object:   cast.Ptr(tuple.Object),
+	}
+
+	resp, err := c.fga.Read(ctx).Options(opts).Body(body).Execute()
+	if err != nil {
+		return false, err
+	}
+
+	return len(resp.GetTuples()) > 0, nil
+}
+
+// ReadTuples reads all tuples of the store.
+func (c *Client) ReadTuples(ctx context.Context, store string) ([]Tuple, error) {
+	tuples := []Tuple{}
+	opts := openfga.ClientReadOptions{
+		StoreId: cast.Ptr(store),
+	}
+
+	for {
+		resp, err := c.fga.Read(ctx).Options(opts).Body(openfga.ClientReadRequest{}).Execute()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range resp.GetTuples() {
+			key := t.GetKey()
+
+			tuple := Tuple{
+				User:     key.GetUser(),
+				Relation: key.GetRelation(),
+				Object:   key.GetObject(),
+			}
+
+			if cond, ok := key.GetConditionOk(); ok {
+				tuple.Condition = &Condition{
+					Name:    cond.GetName(),
+					Context: cond.GetContext(),
+				}
+			}
+
+			tuples = append(tuples, tuple)
+		}
+
+		if resp.GetContinuationToken() == "" {
+			break
+		}
+
+		opts.ContinuationToken = cast.Ptr(resp.GetContinuationToken())
+	}
+
+	return tuples, nil
+}
@@ -0,0 +1,321 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+)
+
+// SchemaVersions are the supported schema versions of authorization models.
+// Modular models use the schema version 1.2.
+var SchemaVersions = []string{"1.1", "1.2"}
+
+// ModelError is a semantic error in an authorization model.
+type ModelError struct {
+	// Line is the line of the error, starting at 1.
+	Line int
+	// Column is the column of the error, starting at 0.
+	Column int
+	// Msg is the message of the error.
+	Msg string
+}
+
+// Error ...
+func (e *ModelError) Error() string {
+	// models in JSON have no positions
+	if e.Line == 0 {
+		return fmt.Sprintf("semantic error: %s", e.Msg)
+	}
+
+	return fmt.Sprintf("semantic error at line=%d, column=%d: %s", e.Line, e.Column, e.Msg)
+}
+
+// ValidateModel parses the model and validates it semantically.
+// Models in DSL and JSON are validated the same way, errors in models in DSL
+// have the line and column of the type or relation.
+// It returns syntax errors and semantic errors (e.g. undefined types or relations,
+// invalid tupleset relations and cyclic relations).
+func ValidateModel(spec string) error {
+	model, err := parseModel(spec)
+	if err != nil {
+		return err
+	}
+
+	v := &validator{
+		model:     model,
+		positions: positions(spec),
+		types:     map[string]*openfgav1.TypeDefinition{},
+	}
+
+	return v.validate()
+}
+
+type position struct {
+	line   int
+	column int
+}
+
+type validator struct {
+	model     *openfgav1.AuthorizationModel
+	positions map[string]position
+	types     map[string]*openfgav1.TypeDefinition
+	errs      []error
+}
+
+func (v *validator) validate() error {
+	if !slices.Contains(SchemaVersions, v.model.GetSchemaVersion()) {
+		v.errorf("schema", "unsupported schema version %s, expected one of %s", v.model.GetSchemaVersion(), strings.Join(SchemaVersions, ", "))
+	}
+
+	for _, td := range v.model.GetTypeDefinitions() {
+		if _, ok := v.types[td.GetType()]; ok {
+			v.errorf("type "+td.GetType(), "type %s is defined more than once", td.GetType())
+		}
+
+		v.types[td.GetType()] = td
+	}
+
+	for _, td := range v.model.GetTypeDefinitions() {
+		for _, relation := range v.relations(td) {
+			v.validateDirectTypes(td, relation)
+			v.validateRewrite(td, relation, td.GetRelations()[relation])
+		}
+	}
+
+	// cycles are only checked on models that reference defined types and relations
+	if len(v.errs) == 0 {
+		v.validateEntrypoints()
+	}
+
+	return errors.Join(v.errs...)
+}
+
+func (v *validator) validateDirectTypes(td *openfgav1.TypeDefinition, relation string) {
+	for _, ref := range v.directTypes(td.GetType(), relation) {
+		t, ok := v.types[ref.GetType()]
+		if !ok {
+			v.errorf(key(td.GetType(), relation), "undefined type %s in relation %s of type %s", ref.GetType(), relation, td.GetType())
+			continue
+		}
+
+		if ref.GetRelation() != "" {
+			if _, ok := t.GetRelations()[ref.GetRelation()]; !ok {
+				v.errorf(key(td.GetType(), relation), "undefined relation %s#%s in relation %s of type %s", ref.GetType(), ref.GetRelation(), relation, td.GetType())
+			}
+		}
+
+		if ref.GetCondition() != "" {
+			if _, ok := v.model.GetConditions()[ref.GetCondition()]; !ok {
+				v.errorf(key(td.GetType(), relation), "undefined condition %s in relation %s of type %s", ref.GetCondition(), relation, td.GetType())
+			}
+		}
+	}
+}
+
+func (v *validator) validateRewrite(td *openfgav1.TypeDefinition, relation string, rewrite *openfgav1.Userset) {
+	switch u := rewrite.GetUserset().(type) {
+	case *openfgav1.Userset_This:
+		if len(v.directTypes(td.GetType(), relation)) == 0 {
+			v.errorf(key(td.GetType(), relation), "relation %s of type %s is directly assignable but has no types", relation, td.GetType())
+		}
+	case *openfgav1.Userset_ComputedUserset:
+		computed := u.ComputedUserset.GetRelation()
+		if _, ok := td.GetRelations()[computed]; !ok {
+			v.errorf(key(td.GetType(), relation), "undefined relation %s in relation %s of type %s", computed, relation, td.GetType())
+		}
+	case *openfgav1.Userset_TupleToUserset:
+		v.validateTupleToUserset(td, relation, u.TupleToUserset)
+	case *openfgav1.Userset_Union:
+		for _, child := range u.Union.GetChild() {
+			v.validateRewrite(td, relation, child)
+		}
+	case *openfgav1.Userset_Intersection:
+		for _, child := range u.Intersection.GetChild() {
+			v.validateRewrite(td, relation, child)
+		}
+	case *openfgav1.Userset_Difference:
+		v.validateRewrite(td, relation, u.Difference.GetBase())
+		v.validateRewrite(td, relation, u.Difference.GetSubtract())
+	}
+}
+
+func (v *validator) validateTupleToUserset(td *openfgav1.TypeDefinition, relation string, ttu *openfgav1.TupleToUserset) {
+	tupleset := ttu.GetTupleset().GetRelation()
+	computed := ttu.GetComputedUserset().GetRelation()
+
+	rewrite, ok := td.GetRelations()[tupleset]
+	if !ok {
+		v.errorf(key(td.GetType(), relation), "undefined relation %s in %s from %s of type %s", tupleset, computed, tupleset, td.GetType())
+		return
+	}
+
+	if _, ok := rewrite.GetUserset().(*openfgav1.Userset_This); !ok {
+		v.errorf(key(td.GetType(), relation), "relation %s in %s from %s of type %s must be directly assignable", tupleset, computed, tupleset, td.GetType())
+		return
+	}
+
+	defined := false
+	for _, ref := range v.directTypes(td.GetType(), tupleset) {
+		if ref.GetRelation() != "" || ref.GetWildcard() != nil {
+			v.errorf(key(td.GetType(), relation), "relation %s in %s from %s of type %s may only relate to types", tupleset, computed, tupleset, td.GetType())
+			return
+		}
+
+		if t, ok := v.types[ref.GetType()]; ok {
+			if _, ok := t.GetRelations()[computed]; ok {
+				defined = true
+			}
+		}
+	}
+
+	if !defined {
+		v.errorf(key(td.GetType(), relation), "relation %s in %s from %s of type %s is not defined on any type of %s", computed, computed, tupleset, td.GetType(), tupleset)
+	}
+}
+
+// validateEntrypoints checks that every relation can be resolved to a user,
+// which fails for relations that only reference each other in a cycle.
+func (v *validator) validateEntrypoints() {
+	resolved := map[string]bool{}
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, td := range v.model.GetTypeDefinitions() {
+			for relation, rewrite := range td.GetRelations() {
+				if resolved[key(td.GetType(), relation)] {
+					continue
+				}
+
+				if v.hasEntrypoint(td, relation, rewrite, resolved) {
+					resolved[key(td.GetType(), relation)] = true
+					changed = true
+				}
+			}
+		}
+	}
+
+	for _, td := range v.model.GetTypeDefinitions() {
+		for _, relation := range v.relations(td) {
+			if !resolved[key(td.GetType(), relation)] {
+				v.errorf(key(td.GetType(), relation), "relation %s of type %s has no entrypoint, it only references itself in a cycle", relation, td.GetType())
+			}
+		}
+	}
+}
+
+func (v *validator) hasEntrypoint(td *openfgav1.TypeDefinition, relation string, rewrite *openfgav1.Userset, resolved map[string]bool) bool {
+	switch u := rewrite.GetUserset().(type) {
+	case *openfgav1.Userset_This:
+		for _, ref := range v.directTypes(td.GetType(), relation) {
+			if ref.GetRelation() == "" || resolved[key(ref.GetType(), ref.GetRelation())] {
+				return true
+			}
+		}
+
+		return false
+	case *openfgav1.Userset_ComputedUserset:
+		return resolved[key(td.GetType(), u.ComputedUserset.GetRelation())]
+	case *openfgav1.Userset_TupleToUserset:
+		for _, ref := range v.directTypes(td.GetType(), u.TupleToUserset.GetTupleset().GetRelation()) {
+			if resolved[key(ref.GetType(), u.TupleToUserset.GetComputedUserset().GetRelation())] {
+				return true
+			}
+		}
+
+		return false
+	case *openfgav1.Userset_Union:
+		return slices.ContainsFunc(u.Union.GetChild(), func(child *openfgav1.Userset) bool {
+			return v.hasEntrypoint(td, relation, child, resolved)
+		})
+	case *openfgav1.Userset_Intersection:
+		return !slices.ContainsFunc(u.Intersection.GetChild(), func(child *openfgav1.Userset) bool {
+			return !v.hasEntrypoint(td, relation, child, resolved)
+		})
+	case *openfgav1.Userset_Difference:
+		return v.hasEntrypoint(td, relation, u.Difference.GetBase(), resolved)
+	}
+
+	return false
+}
+
+func (v *validator) directTypes(typ, relation string) []*openfgav1.RelationReference {
+	td, ok := v.types[typ]
+	if !ok {
+		return nil
+	}
+
+	return td.GetMetadata().GetRelations()[relation].GetDirectlyRelatedUserTypes()
+}
+
+// relations returns the relations of the type in the order of their definition.
+func (v *validator) relations(td *openfgav1.TypeDefinition) []string {
+	relations := make([]string, 0, len(td.GetRelations()))
+	for relation := range td.GetRelations() {
+		relations = append(relations, relation)
+	}
+
+	slices.Sort(relations)
+	slices.SortStableFunc(relations, func(a, b string) int {
+		return v.positions[key(td.GetType(), a)].line - v.positions[key(td.GetType(), b)].line
+	})
+
+	return relations
+}
+
+func (v *validator) errorf(at string, format string, args ...any) {
+	pos := v.positions[at]
+	v.errs = append(v.errs, &ModelError{Line: pos.line, Column: pos.column, Msg: fmt.Sprintf(format, args...)})
+}
+
+func key(typ, relation string) string {
+	return typ + "#" + relation
+}
+
+// positions returns the positions of the schema, the types, the extended types, the conditions
+// and the relations in the model. Relations are keyed by the type or extended type they are defined in.
+func positions(spec string) map[string]position {
+	pos := map[string]position{}
+	typ := ""
+
+	for i, line := range strings.Split(spec, "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		column := len(line) - len(trimmed)
+		fields := strings.Fields(trimmed)
+
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "schema":
+			pos["schema"] = position{line: i + 1, column: column}
+		case "type":
+			typ = fields[1]
+			pos["type "+typ] = position{line: i + 1, column: column}
+		case "extend":
+			if fields[1] != "type" || len(fields) < 3 {
+				continue
+			}
+
+			typ = fields[2]
+			if _, ok := pos["type "+typ]; !ok {
+				pos["type "+typ] = position{line: i + 1, column: column}
+			}
+		case "condition":
+			// relations are never defined in conditions
+			typ = ""
+			name := strings.TrimSpace(strings.SplitN(strings.TrimPrefix(trimmed, "condition"), "(", 2)[0])
+			pos["condition "+name] = position{line: i + 1, column: column}
+		case "define":
+			definition := strings.TrimLeft(strings.TrimPrefix(trimmed, "define"), " \t")
+			relation := strings.TrimSpace(strings.SplitN(definition, ":", 2)[0])
+			pos[key(typ, relation)] = position{line: i + 1, column: column + len(trimmed) - len(definition)}
+		}
+	}
+
+	return pos
+}
@@ -0,0 +1,246 @@
+package client
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateModel(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   string
+		line   int
+		column int
+		msg    string
+	}{
+		{
+			name: "unsupported schema version",
+			spec: `model
+  schema 1.0
+type user`,
+			line:   2,
+			column: 2,
+			msg:    "unsupported schema version 1.0",
+		},
+		{
+			name: "duplicate type",
+			spec: `model
+  schema 1.1
+type user
+type user`,
+			line:   4,
+			column: 0,
+			msg:    "type user is defined more than once",
+		},
+		{
+			name: "undefined type",
+			spec: `model
+  schema 1.1
+type user
+type document
+  relations
+    define viewer: [group]`,
+			line:   6,
+			column: 11,
+			msg:    "undefined type group in relation viewer of type document",
+		},
+		{
+			name: "undefined relation of type",
+			spec: `model
+  schema 1.1
+type user
+type document
+  relations
+    define viewer: [user#member]`,
+			line:   6,
+			column: 11,
+			msg:    "undefined relation user#member in relation viewer of type document",
+		},
+		{
+			name: "undefined condition",
+			spec: `model
+  schema 1.1
+type user
+type document
+  relations
+    define viewer: [user with unknown]`,
+			line:   6,
+			column: 11,
+			msg:    "undefined condition unknown in relation viewer of type document",
+		},
+		{
+			name:   "directly assignable without types",
+			spec:   `{"schema_version":"1.1","type_definitions":[{"type":"user"},{"type":"document","relations":{"viewer":{"this":{}}},"metadata":{"relations":{"viewer":{"directly_related_user_types":[]}}}}]}`,
+			line:   0,
+			column: 0,
+			msg:    "relation viewer of type document is directly assignable but has no types",
+		},
+		{
+			name: "undefined computed relation",
+			spec: `model
+  schema 1.1
+type user
+type document
+  relations
+    define viewer: editor`,
+			line:   6,
+			column: 11,
+			msg:    "undefined relation editor in relation viewer of type document",
+		},
+		{
+			name: "undefined tupleset relation",
+			spec: `model
+  schema 1.1
+type user
+type document
+  relations
+    define viewer: viewer from parent`,
+			line:   6,
+			column: 11,
+			msg:    "undefined relation parent in viewer from parent of type document",
+		},
+		{
+			name: "tupleset relation not directly assignable",
+			spec: `model
+  schema 1.1
+type user
+type document
+  relations
+    define owner: [user]
+    define parent: owner
+    define viewer: owner from parent`,
+			line:   8,
+			column: 11,
+			msg:    "relation parent in owner from parent of type document must be directly assignable",
+		},
+		{
+			name: "tupleset relation with usersets",
+			spec: `model
+  schema 1.1
+type user
+type folder
+  relations
+    define viewer: [user]
+type document
+  relations
+    define parent: [folder#viewer]
+    define viewer: viewer from parent`,
+			line:   10,
+			column: 11,
+			msg:    "relation parent in viewer from parent of type document may only relate to types",
+		},
+		{
+			name: "computed relation not defined on tupleset types",
+			spec: `model
+  schema 1.1
+type user
+type folder
+  relations
+    define owner: [user]
+type document
+  relations
+    define parent: [folder]
+    define viewer: viewer from parent`,
+			line:   10,
+			column: 11,
+			msg:    "relation viewer in viewer from parent of type document is not defined on any type of parent",
+		},
+		{
+			name: "cycle without entrypoint",
+			spec: `model
+  schema 1.1
+type user
+type document
+  relations
+    define editor: viewer
+    define viewer: editor`,
+			line:   6,
+			column: 11,
+			msg:    "relation editor of type document has no entrypoint",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateModel(tc.spec)
+
+			var modelErr *ModelError
+			if !errors.As(err, &modelErr) {
+				t.Fatalf("expected a model error, got %v", err)
+			}
+
+			if modelErr.Line != tc.line || modelErr.Column != tc.column {
+				t.Errorf("expected the error at line=%d, column=%d, got line=%d, column=%d", tc.line, tc.column, modelErr.Line, modelErr.Column)
+			}
+
+			if !strings.Contains(modelErr.Msg, tc.msg) {
+				t.Errorf("expected the error %q, got %q", tc.msg, modelErr.Msg)
+			}
+		})
+	}
+}
+
+func TestValidateModelValid(t *testing.T) {
+	spec := `model
+  schema 1.1
+type user
+type folder
+  relations
+    define viewer: [user]
+type document
+  relations
+    define parent: [folder]
+    define owner: [user, user with non_expired]
+    define viewer: [user] or owner or viewer from parent
+
+condition non_expired(current_time: timestamp, expires: timestamp) {
+  current_time < expires
+}`
+
+	if err := ValidateModel(spec); err != nil {
+		t.Fatalf("expected a valid model, got %v", err)
+	}
+}
+
+func TestPositions(t *testing.T) {
+	spec := `module documents
+
+extend type folder
+  relations
+    define viewer: [user]
+
+type document
+  relations
+    define viewer: [user]
+
+condition non_expired(current_time: timestamp) {
+  current_time < timestamp("2030-01-01T00:00:00Z")
+}`
+
+	tests := []struct {
+		key    string
+		line   int
+		column int
+	}{
+		{key: "type folder", line: 3, column: 0},
+		{key: "folder#viewer", line: 5, column: 11},
+		{key: "type document", line: 7, column: 0},
+		{key: "document#viewer", line: 9, column: 11},
+		{key: "condition non_expired", line: 11, column: 0},
+	}
+
+	pos := positions(spec)
+
+	for _, tc := range tests {
+		p, ok := pos[tc.key]
+		if !ok {
+			t.Errorf("expected a position of %s", tc.key)
+			continue
+		}
+
+		if p.line != tc.line || p.column != tc.column {
+			t.Errorf("expected %s at line=%d, column=%d, got line=%d, column=%d", tc.key, tc.line, tc.column, p.line, p.column)
+		}
+	}
+}